@@ -0,0 +1,524 @@
+package anomalia
+
+import (
+	"errors"
+	"math"
+)
+
+// ARIMAFitMethod type checker for the ARIMA fitting method
+type ARIMAFitMethod int32
+
+const (
+	// CSS fits the model by minimizing the conditional sum of squares.
+	CSS ARIMAFitMethod = iota
+
+	// MLE fits the model by maximizing the exact Gaussian likelihood computed with a Kalman filter.
+	// It is more accurate on short time series but slower, especially with long seasonal periods.
+	MLE
+)
+
+// ARIMA holds the (seasonal) AutoRegressive Integrated Moving Average model configuration.
+//
+// The model is fitted on the differenced time series and the autoregressive and moving average
+// coefficients are constrained to the stationary and invertible regions. When neither regular nor seasonal
+// differencing is used, the mean is estimated by the sample mean.
+//
+// As an anomaly detection algorithm, it scores each point by its standardized one-step ahead residual.
+// For more details, check: https://otexts.com/fpp2/arima.html
+type ARIMA struct {
+	p, d, q                                 int
+	seasonalP, seasonalD, seasonalQ, period int
+	method                                  ARIMAFitMethod
+	auto                                    bool
+	maxP, maxQ, maxSeasonalP, maxSeasonalQ  int
+	model                                   *arimaModel
+	values                                  []float64
+	forecastState
+}
+
+// arimaModel holds the fitted coefficients where seasonal and non seasonal polynomials are multiplied together.
+type arimaModel struct {
+	p, q, seasonalP, seasonalQ int
+	ar, ma                     []float64
+	mean                       float64
+	aic                        float64
+	residuals                  []float64
+}
+
+// NewARIMA returns an ARIMA(p, d, q) instance.
+func NewARIMA(p, d, q int) *ARIMA {
+	return &ARIMA{p: p, d: d, q: q, period: 1, method: CSS}
+}
+
+// Seasonal sets the seasonal order (P, D, Q) and period of the model.
+func (a *ARIMA) Seasonal(p, d, q, period int) *ARIMA {
+	a.seasonalP, a.seasonalD, a.seasonalQ, a.period = p, d, q, period
+	return a
+}
+
+// Method sets the fitting method (defaults to CSS).
+func (a *ARIMA) Method(method ARIMAFitMethod) *ARIMA {
+	a.method = method
+	return a
+}
+
+// Auto enables the automatic order selection: all the autoregressive and moving average orders
+// up to the specified maximal orders are fitted and the model with the lowest AIC is kept.
+// The differencing orders are not selected and must be set explicitly.
+func (a *ARIMA) Auto(maxP, maxQ, maxSeasonalP, maxSeasonalQ int) *ARIMA {
+	a.auto = true
+	a.maxP, a.maxQ, a.maxSeasonalP, a.maxSeasonalQ = maxP, maxQ, maxSeasonalP, maxSeasonalQ
+	return a
+}
+
+// Order returns the non seasonal and seasonal orders of the fitted model.
+func (a *ARIMA) Order() (p, d, q, seasonalP, seasonalD, seasonalQ int) {
+	if a.model == nil {
+		return a.p, a.d, a.q, a.seasonalP, a.seasonalD, a.seasonalQ
+	}
+	return a.model.p, a.d, a.model.q, a.model.seasonalP, a.seasonalD, a.model.seasonalQ
+}
+
+// AIC returns the Akaike Information Criterion of the fitted model.
+func (a *ARIMA) AIC() float64 {
+	if a.model == nil {
+		return math.NaN()
+	}
+	return a.model.aic
+}
+
+// Fit fits the model on the time series.
+func (a *ARIMA) Fit(timeSeries *TimeSeries) error {
+	if a.d < 0 || a.seasonalD < 0 || (a.period < 2 && (a.seasonalP > 0 || a.seasonalD > 0 || a.seasonalQ > 0)) {
+		return errors.New("invalid differencing orders or seasonal period")
+	}
+
+	// Remove the trend and seasonality by differencing
+	x := copySlice(timeSeries.Values)
+	for i := 0; i < a.d; i++ {
+		x = difference(x, 1)
+	}
+	for i := 0; i < a.seasonalD; i++ {
+		x = difference(x, a.period)
+	}
+	mean := 0.0
+	if a.d+a.seasonalD == 0 && len(x) > 0 {
+		mean = Average(x)
+		x = mapSlice(x, func(value float64) float64 { return value - mean })
+	}
+
+	var (
+		best *arimaModel
+		err  error
+	)
+	if a.auto {
+		for p := 0; p <= a.maxP; p++ {
+			for q := 0; q <= a.maxQ; q++ {
+				for seasonalP := 0; seasonalP <= a.maxSeasonalP; seasonalP++ {
+					for seasonalQ := 0; seasonalQ <= a.maxSeasonalQ; seasonalQ++ {
+						model, fitErr := a.fitOrder(x, mean, p, q, seasonalP, seasonalQ)
+						if fitErr == nil && (best == nil || model.aic < best.aic) {
+							best = model
+						}
+						err = fitErr
+					}
+				}
+			}
+		}
+		if best != nil {
+			err = nil
+		}
+	} else {
+		best, err = a.fitOrder(x, mean, a.p, a.q, a.seasonalP, a.seasonalQ)
+	}
+	if err != nil {
+		return err
+	}
+
+	a.model = best
+	a.values = copySlice(timeSeries.Values)
+
+	// Residuals of the differenced series are the residuals of the original series
+	offset := timeSeries.Size() - len(x)
+	fitted := make([]float64, timeSeries.Size())
+	for t := range fitted {
+		if t < offset+len(best.ar) {
+			fitted[t] = math.NaN()
+		} else {
+			fitted[t] = timeSeries.Values[t] - best.residuals[t-offset]
+		}
+	}
+	a.fit(timeSeries, fitted)
+	return nil
+}
+
+// Predict returns the forecast of the next horizon points.
+func (a *ARIMA) Predict(horizon int) (*TimeSeries, error) {
+	if err := checkHorizon(horizon); err != nil {
+		return nil, err
+	}
+	return a.predict(a.points(horizon))
+}
+
+// PredictInterval returns the forecast of the next horizon points with their prediction interval.
+func (a *ARIMA) PredictInterval(horizon int, level float64) (*Forecast, error) {
+	if err := checkHorizon(horizon); err != nil {
+		return nil, err
+	}
+	if a.model == nil {
+		return nil, errors.New("model must be fitted first")
+	}
+
+	// The forecast variance grows with the sum of squared psi weights of the integrated model
+	fullAR := a.integratedAR()
+	psi := make([]float64, horizon)
+	for j := range psi {
+		if j == 0 {
+			psi[j] = 1
+			continue
+		}
+		if j <= len(a.model.ma) {
+			psi[j] = a.model.ma[j-1]
+		}
+		for k := 1; k <= minInt(j, len(fullAR)); k++ {
+			psi[j] += fullAR[k-1] * psi[j-k]
+		}
+	}
+
+	return a.predictInterval(a.points(horizon), level, func(h int) float64 {
+		return sumOfSquares(psi[:h])
+	})
+}
+
+// Run runs the ARIMA model over the time series.
+func (a *ARIMA) Run(timeSeries *TimeSeries) *ScoreList {
+	scoreList, _ := a.computeScores(timeSeries)
+	return scoreList
+}
+
+func (a *ARIMA) computeScores(timeSeries *TimeSeries) (*ScoreList, error) {
+	if err := a.Fit(timeSeries); err != nil {
+		return nil, err
+	}
+
+	scores := mapSliceWithIndex(timeSeries.Values, func(idx int, value float64) float64 {
+		if math.IsNaN(a.fitted[idx]) || a.sigma == 0 {
+			return 0.0
+		}
+		return math.Abs(value-a.fitted[idx]) / a.sigma
+	})
+	return &ScoreList{timeSeries.Timestamps, scores}, nil
+}
+
+func (a *ARIMA) fitOrder(x []float64, mean float64, p, q, seasonalP, seasonalQ int) (*arimaModel, error) {
+	model := &arimaModel{p: p, q: q, seasonalP: seasonalP, seasonalQ: seasonalQ, mean: mean}
+	nParams := p + q + seasonalP + seasonalQ
+	arOrder, maOrder := p+seasonalP*a.period, q+seasonalQ*a.period
+	if len(x) <= arOrder+maOrder+nParams+1 {
+		return nil, errors.New("not enough data points")
+	}
+
+	var objective func([]float64) float64
+	switch a.method {
+	case CSS:
+		objective = func(params []float64) float64 {
+			ar, ma := a.expand(params, p, q, seasonalP, seasonalQ)
+			_, css := cssResiduals(x, ar, ma)
+			return float64(len(x)-len(ar)) * math.Log(css)
+		}
+	case MLE:
+		objective = func(params []float64) float64 {
+			ar, ma := a.expand(params, p, q, seasonalP, seasonalQ)
+			return armaLikelihood(x, ar, ma)
+		}
+	default:
+		return nil, errors.New("unsupported fitting method")
+	}
+
+	params := minimize(objective, make([]float64, nParams))
+	model.ar, model.ma = a.expand(params, p, q, seasonalP, seasonalQ)
+
+	var css float64
+	model.residuals, css = cssResiduals(x, model.ar, model.ma)
+
+	// -2 * log likelihood
+	var deviance float64
+	if a.method == MLE {
+		deviance = armaLikelihood(x, model.ar, model.ma)
+	} else {
+		n := float64(len(x) - len(model.ar))
+		deviance = n * (math.Log(2*math.Pi*css/n) + 1)
+	}
+
+	// Estimated parameters include the innovations variance and the mean
+	k := float64(nParams + 1)
+	if a.d+a.seasonalD == 0 {
+		k++
+	}
+	model.aic = deviance + 2*k
+	return model, nil
+}
+
+// expand transforms the unconstrained parameters into the multiplied autoregressive and moving average coefficients.
+func (a *ARIMA) expand(params []float64, p, q, seasonalP, seasonalQ int) ([]float64, []float64) {
+	phi := partialToCoefficients(params[:p])
+	seasonalPhi := partialToCoefficients(params[p : p+seasonalP])
+	theta := partialToCoefficients(params[p+seasonalP : p+seasonalP+q])
+	seasonalTheta := partialToCoefficients(params[p+seasonalP+q:])
+
+	arPoly := polyMultiply(lagPolynomial(phi, 1, -1), lagPolynomial(seasonalPhi, a.period, -1))
+	maPoly := polyMultiply(lagPolynomial(theta, 1, -1), lagPolynomial(seasonalTheta, a.period, -1))
+
+	ar := make([]float64, len(arPoly)-1)
+	for k := range ar {
+		ar[k] = -arPoly[k+1]
+	}
+	ma := make([]float64, len(maPoly)-1)
+	for k := range ma {
+		ma[k] = maPoly[k+1]
+	}
+	return ar, ma
+}
+
+// integratedAR returns the autoregressive coefficients of the model including the differencing operators.
+func (a *ARIMA) integratedAR() []float64 {
+	poly := lagPolynomial(a.model.ar, 1, -1)
+	for i := 0; i < a.d; i++ {
+		poly = polyMultiply(poly, lagPolynomial([]float64{1}, 1, -1))
+	}
+	for i := 0; i < a.seasonalD; i++ {
+		poly = polyMultiply(poly, lagPolynomial([]float64{1}, a.period, -1))
+	}
+
+	coefficients := make([]float64, len(poly)-1)
+	for k := range coefficients {
+		coefficients[k] = -poly[k+1]
+	}
+	return coefficients
+}
+
+func (a *ARIMA) points(horizon int) []float64 {
+	points := make([]float64, horizon)
+	if a.model == nil {
+		return points
+	}
+
+	var (
+		fullAR    = a.integratedAR()
+		n         = len(a.values)
+		offset    = n - len(a.model.residuals)
+		values    = append(copySlice(a.values), points...)
+		residuals = make([]float64, n+horizon)
+		constant  = 0.0
+	)
+	copy(residuals[offset:], a.model.residuals)
+	if a.d+a.seasonalD == 0 {
+		constant = a.model.mean * (1 - SumFloat64s(a.model.ar))
+	}
+
+	for h := 0; h < horizon; h++ {
+		t := n + h
+		prediction := constant
+		for k, coefficient := range fullAR {
+			if t-1-k >= 0 {
+				prediction += coefficient * values[t-1-k]
+			}
+		}
+		for j, coefficient := range a.model.ma {
+			if t-1-j >= 0 {
+				prediction += coefficient * residuals[t-1-j]
+			}
+		}
+		values[t] = prediction
+		points[h] = prediction
+	}
+	return points
+}
+
+// cssResiduals returns the conditional residuals of the ARMA model and their sum of squares.
+// The first residuals, which cannot be conditioned on enough past values, are set to 0.
+func cssResiduals(x, ar, ma []float64) ([]float64, float64) {
+	residuals := make([]float64, len(x))
+	sum := 0.0
+	for t := len(ar); t < len(x); t++ {
+		prediction := 0.0
+		for i, coefficient := range ar {
+			prediction += coefficient * x[t-1-i]
+		}
+		for j, coefficient := range ma {
+			if t-1-j >= 0 {
+				prediction += coefficient * residuals[t-1-j]
+			}
+		}
+		residuals[t] = x[t] - prediction
+		sum += residuals[t] * residuals[t]
+	}
+	return residuals, sum
+}
+
+// armaLikelihood returns -2 times the exact concentrated Gaussian log likelihood of the ARMA model.
+// It uses a Kalman filter on the state space representation of the model.
+func armaLikelihood(x, ar, ma []float64) float64 {
+	r := maxInt(len(ar), len(ma)+1)
+	transition := make([]float64, r)
+	copy(transition, ar)
+	noise := make([]float64, r)
+	noise[0] = 1
+	copy(noise[1:], ma)
+
+	// Multiplies the matrix by the (sparse) transition matrix from the left and by its transpose from the right
+	propagate := func(m [][]float64) [][]float64 {
+		left := newMatrix(r, r)
+		for i := 0; i < r; i++ {
+			for j := 0; j < r; j++ {
+				left[i][j] = transition[i] * m[0][j]
+				if i+1 < r {
+					left[i][j] += m[i+1][j]
+				}
+			}
+		}
+		result := newMatrix(r, r)
+		for i := 0; i < r; i++ {
+			for j := 0; j < r; j++ {
+				result[i][j] = left[i][0] * transition[j]
+				if j+1 < r {
+					result[i][j] += left[i][j+1]
+				}
+				result[i][j] += noise[i] * noise[j]
+			}
+		}
+		return result
+	}
+
+	covariance, converged := stationaryCovariance(transition, noise)
+	if !converged {
+		return math.Inf(1)
+	}
+
+	state := make([]float64, r)
+	sumOfScaledSquares, sumOfLogs := 0.0, 0.0
+	for _, value := range x {
+		innovation := value - state[0]
+		variance := covariance[0][0]
+		if variance <= 0 || math.IsNaN(variance) {
+			return math.Inf(1)
+		}
+		sumOfScaledSquares += innovation * innovation / variance
+		sumOfLogs += math.Log(variance)
+
+		// Update the state with the observation
+		gain := make([]float64, r)
+		for i := range gain {
+			gain[i] = covariance[i][0] / variance
+			state[i] += gain[i] * innovation
+		}
+		updated := newMatrix(r, r)
+		for i := range updated {
+			for j := range updated[i] {
+				updated[i][j] = covariance[i][j] - gain[i]*covariance[0][j]
+			}
+		}
+
+		// Predict the next state
+		next := make([]float64, r)
+		for i := range next {
+			next[i] = transition[i] * state[0]
+			if i+1 < r {
+				next[i] += state[i+1]
+			}
+		}
+		state = next
+		covariance = propagate(updated)
+	}
+
+	n := float64(len(x))
+	sigma2 := sumOfScaledSquares / n
+	return n*math.Log(2*math.Pi*sigma2) + sumOfLogs + n
+}
+
+// stationaryCovariance returns the stationary state covariance P = T P T' + R R' of the state space model,
+// where T is the companion matrix of the transition coefficients and R the noise loadings.
+// It uses the doubling algorithm, P <- P + A P A' and A <- A A, which sums 2^k propagations at step k
+// so that long seasonal periods converge in a few dozen steps. It returns false when the sum diverges.
+func stationaryCovariance(transition, noise []float64) ([][]float64, bool) {
+	r := len(transition)
+	power := newMatrix(r, r)
+	covariance := newMatrix(r, r)
+	for i := 0; i < r; i++ {
+		power[i][0] = transition[i]
+		if i+1 < r {
+			power[i][i+1] = 1
+		}
+		for j := 0; j < r; j++ {
+			covariance[i][j] = noise[i] * noise[j]
+		}
+	}
+
+	for step := 0; step < 64; step++ {
+		increment := multiplyMatrices(multiplyMatrices(power, covariance), transpose(power))
+		change, scale := 0.0, 0.0
+		for i := range covariance {
+			for j := range covariance[i] {
+				covariance[i][j] += increment[i][j]
+				change = math.Max(change, math.Abs(increment[i][j]))
+				scale = math.Max(scale, math.Abs(covariance[i][j]))
+			}
+		}
+		if math.IsNaN(change) || math.IsInf(scale, 0) {
+			return nil, false
+		}
+		if change <= 1e-12*math.Max(scale, 1) {
+			return covariance, true
+		}
+		power = multiplyMatrices(power, power)
+	}
+	return nil, false
+}
+
+// partialToCoefficients maps unconstrained parameters to the coefficients of a stationary
+// autoregressive polynomial: parameters are squashed into partial autocorrelations in (-1, 1)
+// which are converted with the Durbin-Levinson recursion.
+func partialToCoefficients(params []float64) []float64 {
+	coefficients := make([]float64, len(params))
+	previous := make([]float64, len(params))
+	for k, param := range params {
+		partial := math.Tanh(param)
+		copy(previous, coefficients)
+		coefficients[k] = partial
+		for j := 0; j < k; j++ {
+			coefficients[j] = previous[j] - partial*previous[k-1-j]
+		}
+	}
+	return coefficients
+}
+
+// lagPolynomial returns the polynomial 1 + sign * (c1 B^step + c2 B^(2 step) + ...) in the lag operator B.
+func lagPolynomial(coefficients []float64, step int, sign float64) []float64 {
+	poly := make([]float64, len(coefficients)*step+1)
+	poly[0] = 1
+	for i, coefficient := range coefficients {
+		poly[(i+1)*step] = sign * coefficient
+	}
+	return poly
+}
+
+func polyMultiply(a, b []float64) []float64 {
+	product := make([]float64, len(a)+len(b)-1)
+	for i := range a {
+		for j := range b {
+			product[i+j] += a[i] * b[j]
+		}
+	}
+	return product
+}
+
+func difference(values []float64, lag int) []float64 {
+	if len(values) <= lag {
+		return []float64{}
+	}
+	differenced := make([]float64, len(values)-lag)
+	for i := range differenced {
+		differenced[i] = values[i+lag] - values[i]
+	}
+	return differenced
+}
@@ -0,0 +1,156 @@
+package anomalia
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestFitARIMA(t *testing.T) {
+	ts := generateAutoregressiveTimeSeries(500, 0.7)
+	for _, method := range []ARIMAFitMethod{CSS, MLE} {
+		arima := NewARIMA(1, 0, 0).Method(method)
+		if err := arima.Fit(ts); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if coefficient := arima.model.ar[0]; math.Abs(coefficient-0.7) > 0.1 {
+			t.Fatalf("expected coefficient close to 0.7, got %v", coefficient)
+		}
+	}
+}
+
+func TestAutoARIMA(t *testing.T) {
+	ts := generateAutoregressiveTimeSeries(300, 0.7)
+	auto := NewARIMA(0, 0, 0).Auto(2, 2, 0, 0)
+	if err := auto.Fit(ts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reference := NewARIMA(1, 0, 0)
+	_ = reference.Fit(ts)
+	if auto.AIC() > reference.AIC() {
+		t.Fatalf("selected model must have the lowest AIC")
+	}
+
+	if p, _, q, _, _, _ := auto.Order(); p+q == 0 {
+		t.Fatalf("selected model must capture the autocorrelation")
+	}
+}
+
+func TestForecastWithSeasonalARIMA(t *testing.T) {
+	ts := generatePeriodicTimeSeries(120, 12)
+	arima := NewARIMA(0, 0, 0).Seasonal(0, 1, 0, 12)
+	if err := arima.Fit(ts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	forecast, err := arima.PredictInterval(24, 0.95)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for h, value := range forecast.Values {
+		expected := math.Sin(2 * math.Pi * float64(120+h) / 12)
+		if math.Abs(value-expected) > 1e-6 {
+			t.Fatalf("expected %v, got %v", expected, value)
+		}
+	}
+}
+
+func TestFitSeasonalARIMA(t *testing.T) {
+	theta, seasonalTheta := -0.4, -0.6
+	ts := generateAirlineTimeSeries(360, theta, seasonalTheta)
+	for _, method := range []ARIMAFitMethod{CSS, MLE} {
+		arima := NewARIMA(0, 1, 1).Seasonal(0, 1, 1, 12).Method(method)
+		if err := arima.Fit(ts); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// The moving average polynomial is (1 + theta B)(1 + seasonalTheta B^12)
+		ma := arima.model.ma
+		if len(ma) != 13 {
+			t.Fatalf("expected 13 moving average coefficients, got %v", len(ma))
+		}
+		if math.Abs(ma[0]-theta) > 0.1 {
+			t.Fatalf("expected coefficient close to %v, got %v", theta, ma[0])
+		}
+		if math.Abs(ma[11]-seasonalTheta) > 0.1 {
+			t.Fatalf("expected seasonal coefficient close to %v, got %v", seasonalTheta, ma[11])
+		}
+	}
+}
+
+func TestStationaryCovarianceWithLongSeasonalPeriod(t *testing.T) {
+	// An MA(1)x(1)_168 model has a nilpotent transition, so its covariance is the sum of the propagated noise
+	ma := polyMultiply(lagPolynomial([]float64{0.5}, 1, 1), lagPolynomial([]float64{0.8}, 168, 1))[1:]
+	noise := append([]float64{1}, ma...)
+	covariance, converged := stationaryCovariance(make([]float64, len(ma)+1), noise)
+	if !converged {
+		t.Fatalf("stationary covariance must converge")
+	}
+
+	expected := 1.0
+	for _, coefficient := range ma {
+		expected += coefficient * coefficient
+	}
+	if math.Abs(covariance[0][0]-expected) > 1e-9 {
+		t.Fatalf("expected variance %v, got %v", expected, covariance[0][0])
+	}
+
+	if _, converged := stationaryCovariance([]float64{1.5}, []float64{1}); converged {
+		t.Fatalf("stationary covariance of an explosive model must not converge")
+	}
+}
+
+func TestRunWithARIMA(t *testing.T) {
+	ts := generateAutoregressiveTimeSeries(300, 0.7)
+	ts.Values[150] += 10
+
+	scoreList := NewARIMA(1, 0, 0).Run(ts)
+	if scoreList == nil {
+		t.Fatalf("score list cannot be nil")
+	}
+
+	if scoreList.Scores[150] != scoreList.Max() {
+		t.Fatalf("the spike must have the highest score")
+	}
+
+	if scoreList = NewARIMA(1, 0, 0).Run(NewTimeSeries([]float64{1, 2}, []float64{1, 2})); scoreList != nil {
+		t.Fatalf("score list must be nil (not enough data points)")
+	}
+}
+
+func generateAutoregressiveTimeSeries(datasetSize int, coefficient float64) *TimeSeries {
+	random := rand.New(rand.NewSource(42))
+	timestamps := make([]float64, datasetSize)
+	values := make([]float64, datasetSize)
+	for i := 0; i < datasetSize; i++ {
+		timestamps[i] = float64(i) + 1
+		values[i] = random.NormFloat64()
+		if i > 0 {
+			values[i] += coefficient * values[i-1]
+		}
+	}
+	return &TimeSeries{timestamps, values}
+}
+
+// generateAirlineTimeSeries simulates a (0,1,1)x(0,1,1)_12 model.
+func generateAirlineTimeSeries(datasetSize int, theta, seasonalTheta float64) *TimeSeries {
+	random := rand.New(rand.NewSource(7))
+	noise := make([]float64, datasetSize)
+	timestamps := make([]float64, datasetSize)
+	values := make([]float64, datasetSize)
+	at := func(values []float64, i int) float64 {
+		if i < 0 {
+			return 0.0
+		}
+		return values[i]
+	}
+	for i := 0; i < datasetSize; i++ {
+		noise[i] = random.NormFloat64()
+		timestamps[i] = float64(i) + 1
+		values[i] = noise[i] + theta*at(noise, i-1) + seasonalTheta*at(noise, i-12) + theta*seasonalTheta*at(noise, i-13) +
+			at(values, i-1) + at(values, i-12) - at(values, i-13)
+	}
+	return &TimeSeries{timestamps, values}
+}
@@ -15,10 +15,14 @@ const minimalPointsInWindows = 50
 // the frequency of similar chunks to determine anomalies scores.
 // The scoring happens by sliding both lagging and future windows.
 type Bitmap struct {
-	chunkSize        int
-	precision        int
-	lagWindowSize    int
-	futureWindowSize int
+	chunkSize            int
+	precision            int
+	lagWindowSize        int
+	futureWindowSize     int
+	lagWindowDuration    float64
+	futureWindowDuration float64
+	scales               []float64
+	gaussianSAX          bool
 }
 
 // NewBitmap returns Bitmap instance.
@@ -43,18 +47,53 @@ func (b *Bitmap) Precision(p int) *Bitmap {
 	return b
 }
 
-// LagWindowSize sets the lag window size (defaults to 0).
+// LagWindowSize sets the lag window size in data points.
+// When 0 (default), it is derived from the time series size.
 func (b *Bitmap) LagWindowSize(size int) *Bitmap {
 	b.lagWindowSize = size
 	return b
 }
 
-// FutureWindowSize sets the future window size (default to 0).
+// FutureWindowSize sets the future window size in data points.
+// When 0 (default), it is derived from the time series size.
 func (b *Bitmap) FutureWindowSize(size int) *Bitmap {
 	b.futureWindowSize = size
 	return b
 }
 
+// LagWindowDuration sets the lag window size in time units.
+// It is converted to data points using the median interval between timestamps
+// and takes precedence over LagWindowSize.
+func (b *Bitmap) LagWindowDuration(duration float64) *Bitmap {
+	b.lagWindowDuration = duration
+	return b
+}
+
+// FutureWindowDuration sets the future window size in time units.
+// It is converted to data points using the median interval between timestamps
+// and takes precedence over FutureWindowSize.
+func (b *Bitmap) FutureWindowDuration(duration float64) *Bitmap {
+	b.futureWindowDuration = duration
+	return b
+}
+
+// Scales enables the multi-resolution mode: the time series is scored once per scale
+// using windows multiplied by the scale factor. Scores of each scale are divided by
+// their standard deviation and the maximal score across scales is kept.
+// Scales whose windows do not fit in the time series are skipped.
+func (b *Bitmap) Scales(scales ...float64) *Bitmap {
+	b.scales = scales
+	return b
+}
+
+// GaussianSAX tells the algorithm to generate the SAX representation from z-normalized values
+// using Gaussian breakpoints instead of equal-height sections over the value range.
+// The precision is then used as alphabet size and must be between 3 and 20.
+func (b *Bitmap) GaussianSAX(use bool) *Bitmap {
+	b.gaussianSAX = use
+	return b
+}
+
 // Run runs the bitmap algorithm over the time series
 func (b *Bitmap) Run(timeSeries *TimeSeries) *ScoreList {
 	scoreList, _ := b.computeScores(timeSeries)
@@ -62,17 +101,74 @@ func (b *Bitmap) Run(timeSeries *TimeSeries) *ScoreList {
 }
 
 func (b *Bitmap) computeScores(timeSeries *TimeSeries) (*ScoreList, error) {
-	// Update both lagging and future windows size
-	b.lagWindowSize = int(0.0125 * float64(len(timeSeries.Timestamps)))
-	b.futureWindowSize = int(0.0125 * float64(len(timeSeries.Timestamps)))
+	lws, fws := b.windowSizes(timeSeries)
+	if len(b.scales) == 0 {
+		return b.computeScoresWithWindows(timeSeries, lws, fws)
+	}
+
+	var (
+		combined *ScoreList
+		lastErr  error
+	)
+	for _, scale := range b.scales {
+		scaledLws, scaledFws := int(scale*float64(lws)), int(scale*float64(fws))
+		scoreList, err := b.computeScoresWithWindows(timeSeries, scaledLws, scaledFws)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		if stdev := Stdev(scoreList.Scores); stdev != 0.0 {
+			scoreList.Scores = mapSlice(scoreList.Scores, func(score float64) float64 {
+				return score / stdev
+			})
+		}
 
+		if combined == nil {
+			combined = scoreList
+		} else {
+			combined = combined.Maximum(scoreList)
+		}
+	}
+	if combined == nil {
+		return nil, lastErr
+	}
+	return combined, nil
+}
+
+// windowSizes resolves both lagging and future windows size in data points.
+// Unset windows default to 1.25% of the time series size, and at least half the minimal points in windows.
+func (b *Bitmap) windowSizes(timeSeries *TimeSeries) (int, int) {
+	lws, fws := b.lagWindowSize, b.futureWindowSize
+	defaultSize := maxInt(int(0.0125*float64(timeSeries.Size())), minimalPointsInWindows/2)
+
+	interval := 0.0
+	if b.lagWindowDuration > 0 || b.futureWindowDuration > 0 {
+		interval = timeSeries.samplingInterval()
+	}
+
+	if b.lagWindowDuration > 0 && interval > 0 {
+		lws = int(math.Round(b.lagWindowDuration / interval))
+	} else if lws <= 0 {
+		lws = defaultSize
+	}
+
+	if b.futureWindowDuration > 0 && interval > 0 {
+		fws = int(math.Round(b.futureWindowDuration / interval))
+	} else if fws <= 0 {
+		fws = defaultSize
+	}
+	return lws, fws
+}
+
+func (b *Bitmap) computeScoresWithWindows(timeSeries *TimeSeries, lws, fws int) (*ScoreList, error) {
 	// Perform sanity check
-	if _, err := b.sanityCheck(timeSeries); err != nil {
+	if _, err := b.sanityCheck(timeSeries, lws, fws); err != nil {
 		return nil, err
 	}
 
 	sax := b.generateSAX(timeSeries)
-	laggingsMaps, futureMaps := b.constructAllSAXChunks(timeSeries, sax)
+	laggingsMaps, futureMaps := b.constructAllSAXChunks(timeSeries, sax, lws, fws)
 	dimension := timeSeries.Size()
 
 	computeScoreBetweenTwoWindows := func(idx int) float64 {
@@ -99,7 +195,7 @@ func (b *Bitmap) computeScores(timeSeries *TimeSeries) (*ScoreList, error) {
 	}
 
 	scores := mapSliceWithIndex(timeSeries.Timestamps, func(idx int, timestamp float64) float64 {
-		if (idx < b.lagWindowSize) || (idx > (dimension - b.futureWindowSize)) {
+		if (idx < lws) || (idx > (dimension - fws)) {
 			return 0.0
 		}
 		return computeScoreBetweenTwoWindows(idx)
@@ -111,6 +207,10 @@ func (b *Bitmap) computeScores(timeSeries *TimeSeries) (*ScoreList, error) {
 
 // generateSAX generates the SAX representation of the time series values
 func (b *Bitmap) generateSAX(timeSeries *TimeSeries) BitmapBinary {
+	if b.gaussianSAX {
+		return discretize(ZNormalize(timeSeries.Values), b.precision)
+	}
+
 	sections := make(map[int]float64)
 	min, max := minMax(timeSeries.Values)
 
@@ -154,11 +254,9 @@ func (b *Bitmap) constructChunkFrequencyMap(sax BitmapBinary) map[BitmapBinary]i
 	return frequencyMap
 }
 
-func (b *Bitmap) constructAllSAXChunks(timeSeries *TimeSeries, sax BitmapBinary) (map[int]map[BitmapBinary]int, map[int]map[BitmapBinary]int) {
+func (b *Bitmap) constructAllSAXChunks(timeSeries *TimeSeries, sax BitmapBinary, lws, fws int) (map[int]map[BitmapBinary]int, map[int]map[BitmapBinary]int) {
 	laggingsMaps := make(map[int]map[BitmapBinary]int)
 	futureMaps := make(map[int]map[BitmapBinary]int)
-	lws := b.lagWindowSize
-	fws := b.futureWindowSize
 	chunkSize := b.chunkSize
 	dimension := timeSeries.Size()
 
@@ -198,10 +296,12 @@ func (b *Bitmap) constructAllSAXChunks(timeSeries *TimeSeries, sax BitmapBinary)
 	return laggingsMaps, futureMaps
 }
 
-func (b *Bitmap) sanityCheck(timeSeries *TimeSeries) (*TimeSeries, error) {
-	windowsDimension := b.lagWindowSize + b.futureWindowSize
-	if (timeSeries.Size() < windowsDimension) || (windowsDimension < minimalPointsInWindows) {
+func (b *Bitmap) sanityCheck(timeSeries *TimeSeries, lws, fws int) (*TimeSeries, error) {
+	if (timeSeries.Size() < lws+fws) || (lws < b.chunkSize) || (fws < b.chunkSize) {
 		return nil, errors.New("not enough data points")
 	}
+	if b.gaussianSAX && (b.precision < minAlphabetSize || b.precision > maxAlphabetSize) {
+		return nil, errors.New("precision must be between 3 and 20 when using Gaussian SAX")
+	}
 	return timeSeries, nil
 }
@@ -24,6 +24,74 @@ func TestRunWithBitmap(t *testing.T) {
 	}
 }
 
+func TestRunBitmapWithGaussianSAX(t *testing.T) {
+	timeSeries := generateFakeTimeSeries(2000)
+	scoreList := NewBitmap().Precision(5).GaussianSAX(true).Run(timeSeries)
+	if scoreList == nil {
+		t.Fatalf("score list cannot be nil")
+	}
+
+	if scoreList = NewBitmap().Precision(2).GaussianSAX(true).Run(timeSeries); scoreList != nil {
+		t.Fatalf("score list must be nil (invalid alphabet size)")
+	}
+}
+
+func TestRunBitmapWithExplicitWindows(t *testing.T) {
+	timeSeries := generateFakeTimeSeries(200)
+	scoreList := NewBitmap().LagWindowSize(10).FutureWindowSize(10).Run(timeSeries)
+	if scoreList == nil {
+		t.Fatalf("score list cannot be nil")
+	}
+
+	// Only points outside of both windows are not scored
+	for idx, score := range scoreList.Scores {
+		if (idx < 10 || idx > 190) && score != 0.0 {
+			t.Fatalf("points outside of the windows must have a zero score")
+		}
+	}
+
+	// Same series sampled every minute
+	timestamps := mapSlice(timeSeries.Timestamps, func(timestamp float64) float64 { return timestamp * 60 })
+	timeSeries = NewTimeSeries(timestamps, timeSeries.Values)
+	withDuration := NewBitmap().LagWindowDuration(600).FutureWindowDuration(600).Run(timeSeries)
+	for i := range scoreList.Scores {
+		if scoreList.Scores[i] != withDuration.Scores[i] {
+			t.Fatalf("window durations must be converted to the same window sizes")
+		}
+	}
+}
+
+func TestRunBitmapWithMultipleScales(t *testing.T) {
+	timeSeries := generateFakeTimeSeries(300)
+	scoreList := NewBitmap().LagWindowSize(10).FutureWindowSize(10).Scales(1, 2, 4).Run(timeSeries)
+	if scoreList == nil {
+		t.Fatalf("score list cannot be nil")
+	}
+
+	if len(scoreList.Scores) != timeSeries.Size() {
+		t.Fatalf("both time series and score list dimensions do not match")
+	}
+}
+
+func TestRunBitmapSkipsScalesThatDoNotFit(t *testing.T) {
+	timeSeries := generateFakeTimeSeries(300)
+	expected := NewBitmap().LagWindowSize(10).FutureWindowSize(10).Scales(1).Run(timeSeries)
+	scoreList := NewBitmap().LagWindowSize(10).FutureWindowSize(10).Scales(1, 20).Run(timeSeries)
+	if scoreList == nil {
+		t.Fatalf("score list cannot be nil")
+	}
+
+	for idx, score := range scoreList.Scores {
+		if score != expected.Scores[idx] || scoreList.Timestamps[idx] != expected.Timestamps[idx] {
+			t.Fatalf("scale that does not fit must be skipped, got %v instead of %v at %d", score, expected.Scores[idx], idx)
+		}
+	}
+
+	if scoreList = NewBitmap().LagWindowSize(10).FutureWindowSize(10).Scales(20, 40).Run(timeSeries); scoreList != nil {
+		t.Fatalf("score list must be nil (no scale fits)")
+	}
+}
+
 func TestRunBitmapWhenNotEnoughDataPoints(t *testing.T) {
 	timeSeries := &TimeSeries{
 		Timestamps: []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
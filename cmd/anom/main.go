@@ -1 +1,179 @@
+// Command anom is a CLI tool for rapid experimentation with the anomalia package.
+//
+// Usage:
+//
+//	anom compare [flags] baseline.csv canary.csv
 package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"text/tabwriter"
+
+	"github.com/project-anomalia/anomalia"
+)
+
+const usage = `Usage: anom <command> [flags] [arguments]
+
+Commands:
+  compare    compare a canary time series against a baseline time series
+
+Run 'anom <command> -h' for the flags of a command.
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command and returns the exit code:
+// 0 on success, 1 when the comparison fails and 2 on invalid usage or input.
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	switch args[0] {
+	case "compare":
+		return compare(args[1:], stdout, stderr)
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stdout, usage)
+		return 0
+	default:
+		fmt.Fprintf(stderr, "anom: unknown command %q\n\n%s", args[0], usage)
+		return 2
+	}
+}
+
+type comparisonOutput struct {
+	Test           string  `json:"test"`
+	Statistic      float64 `json:"statistic"`
+	PValue         float64 `json:"p_value"`
+	EffectSize     float64 `json:"effect_size"`
+	RelativeChange float64 `json:"relative_change"`
+	BaselineSize   int     `json:"baseline_size"`
+	CanarySize     int     `json:"canary_size"`
+	Verdict        string  `json:"verdict"`
+}
+
+func compare(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("compare", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: anom compare [flags] baseline.csv canary.csv")
+		fmt.Fprintln(stderr, "\nCompares the values of two CSV time series (timestamp,value with a header).")
+		fmt.Fprintln(stderr, "Exits with 1 when the verdict is fail.\n\nFlags:")
+		flags.PrintDefaults()
+	}
+
+	var (
+		test      = flags.String("test", "all", "test to run: all, mann-whitney-u, kolmogorov-smirnov or welch-t")
+		alpha     = flags.Float64("alpha", 0.05, "p-value below which a difference is significant")
+		tolerance = flags.Float64("tolerance", 0.2, "absolute effect size below which a significant difference is only marginal")
+		start     = flags.Float64("start", math.Inf(-1), "start timestamp of the compared time period")
+		end       = flags.Float64("end", math.Inf(1), "end timestamp of the compared time period")
+		asJSON    = flags.Bool("json", false, "print the results as JSON")
+	)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return 2
+	}
+
+	method, ok := comparisonTests[*test]
+	if !ok && *test != "all" {
+		fmt.Fprintf(stderr, "anom: unknown test %q\n", *test)
+		return 2
+	}
+
+	series := make([]*anomalia.TimeSeries, 2)
+	for i, path := range flags.Args() {
+		ts, err := anomalia.LoadTimeSeriesFromCSV(path)
+		if err != nil {
+			fmt.Fprintf(stderr, "anom: %v\n", err)
+			return 2
+		}
+		series[i] = ts
+	}
+
+	comparison := anomalia.NewComparison(series[0], series[1]).
+		Significance(*alpha).
+		Tolerance(*tolerance).
+		TimePeriod(*start, *end)
+
+	var (
+		results []anomalia.ComparisonResult
+		err     error
+	)
+	if *test == "all" {
+		results, err = comparison.RunAll()
+	} else {
+		var result *anomalia.ComparisonResult
+		if result, err = comparison.Method(method).Run(); err == nil {
+			results = []anomalia.ComparisonResult{*result}
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "anom: %v\n", err)
+		return 2
+	}
+
+	verdict := anomalia.WorstVerdict(results)
+	if *asJSON {
+		printJSON(stdout, results, verdict)
+	} else {
+		printTable(stdout, results, verdict)
+	}
+
+	if verdict == anomalia.Fail {
+		return 1
+	}
+	return 0
+}
+
+var comparisonTests = map[string]anomalia.ComparisonTest{
+	anomalia.MannWhitneyU.String():      anomalia.MannWhitneyU,
+	anomalia.KolmogorovSmirnov.String(): anomalia.KolmogorovSmirnov,
+	anomalia.WelchT.String():            anomalia.WelchT,
+}
+
+func printTable(w io.Writer, results []anomalia.ComparisonResult, verdict anomalia.Verdict) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TEST\tSTATISTIC\tP-VALUE\tEFFECT SIZE\tCHANGE\tVERDICT")
+	for _, result := range results {
+		fmt.Fprintf(tw, "%s\t%.4g\t%.4g\t%.3f\t%+.2f%%\t%s\n",
+			result.Test, result.Statistic, result.PValue, result.EffectSize, 100*result.RelativeChange, result.Verdict)
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "\nverdict: %s\n", verdict)
+}
+
+func printJSON(w io.Writer, results []anomalia.ComparisonResult, verdict anomalia.Verdict) {
+	output := struct {
+		Results []comparisonOutput `json:"results"`
+		Verdict string             `json:"verdict"`
+	}{Verdict: verdict.String()}
+
+	for _, result := range results {
+		output.Results = append(output.Results, comparisonOutput{
+			Test:           result.Test.String(),
+			Statistic:      result.Statistic,
+			PValue:         result.PValue,
+			EffectSize:     result.EffectSize,
+			RelativeChange: result.RelativeChange,
+			BaselineSize:   result.BaselineSize,
+			CanarySize:     result.CanarySize,
+			Verdict:        result.Verdict.String(),
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(output)
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "anom")
+	if err != nil {
+		t.Fatalf("failed to create a temporary directory: %v", err)
+	}
+	return dir
+}
+
+func writeCSV(t *testing.T, dir, name string, mean float64, seed int64) string {
+	rng := rand.New(rand.NewSource(seed))
+	var builder strings.Builder
+	builder.WriteString("timestamp,value\n")
+	for i := 0; i < 200; i++ {
+		fmt.Fprintf(&builder, "%d,%f\n", i, mean+rng.NormFloat64())
+	}
+
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte(builder.String()), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+	return path
+}
+
+func TestCompare(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	baseline := writeCSV(t, dir, "baseline.csv", 10, 1)
+	similar := writeCSV(t, dir, "similar.csv", 10, 2)
+	regressed := writeCSV(t, dir, "regressed.csv", 12, 3)
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"compare", baseline, similar}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "verdict: pass") {
+		t.Fatalf("expected a pass verdict, got %s", stdout.String())
+	}
+
+	stdout.Reset()
+	if code := run([]string{"compare", "-json", "-test", "welch-t", baseline, regressed}, &stdout, &stderr); code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+
+	var output struct {
+		Results []comparisonOutput `json:"results"`
+		Verdict string             `json:"verdict"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+	if output.Verdict != "fail" || len(output.Results) != 1 || output.Results[0].Test != "welch-t" {
+		t.Fatalf("expected a failing welch-t result, got %+v", output)
+	}
+}
+
+func TestCompareWithInvalidUsage(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	for _, args := range [][]string{
+		{},
+		{"unknown"},
+		{"compare", "baseline.csv"},
+		{"compare", "missing.csv", "missing.csv"},
+		{"compare", "-test", "unknown", "main.go", "main.go"},
+	} {
+		if code := run(args, &stdout, &stderr); code != 2 {
+			t.Fatalf("expected exit code 2 for %v, got %d", args, code)
+		}
+	}
+}
+
+func TestCompareWithMalformedFile(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	for _, content := range []string{"timestamp,value\n1,2\n3,oops\n", "timestamp,value\n1,2\nx,y\n3,4\n"} {
+		path := filepath.Join(dir, "malformed.csv")
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", path, err)
+		}
+
+		var stdout, stderr bytes.Buffer
+		if code := run([]string{"compare", path, path}, &stdout, &stderr); code != 2 {
+			t.Fatalf("expected exit code 2, got %d", code)
+		}
+		if !strings.Contains(stderr.String(), "malformed.csv:3:") {
+			t.Fatalf("expected the failing line to be reported, got %q", stderr.String())
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package anomalia
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// CoOccurrence holds the anomaly co-occurrence analysis configuration.
+//
+// It ranks series by how often their anomalies happen around the anomalies of a target series,
+// and compares the number of co-occurrences with what random chance would produce given how
+// many anomalies each series has over the time period.
+type CoOccurrence struct {
+	anomalies     map[string][]Anomaly
+	before, after float64
+	period        *TimePeriod
+}
+
+// CoOccurrenceResult holds the co-occurrence of the anomalies of a series with the target anomalies.
+// Lags are the signed differences between the closest anomaly of the series and each matched target
+// anomaly: a negative lag means the series had its anomaly first (it leads). Expected is the number of
+// matches random chance would produce, and PValue the probability of at least as many matches by chance.
+type CoOccurrenceResult struct {
+	Name      string
+	Matches   int
+	Targets   int
+	Lags      []float64
+	MedianLag float64
+	Leads     bool
+	Expected  float64
+	PValue    float64
+}
+
+// NewCoOccurrence returns an instance of the co-occurrence analysis over the anomalies of named series.
+func NewCoOccurrence(anomalies map[string][]Anomaly) *CoOccurrence {
+	return &CoOccurrence{
+		anomalies: anomalies,
+		before:    5 * 60 * 1000,
+		after:     5 * 60 * 1000,
+	}
+}
+
+// Tolerance sets how long (in seconds) before and after a target anomaly another anomaly still co-occurs (defaults to 5 minutes).
+func (co *CoOccurrence) Tolerance(before, after float64) *CoOccurrence {
+	co.before = before * 1000
+	co.after = after * 1000
+	return co
+}
+
+// TimePeriod sets the observed time period used to estimate chance co-occurrences.
+// When not set, it spans from the earliest to the latest anomaly of all series.
+func (co *CoOccurrence) TimePeriod(start, end float64) *CoOccurrence {
+	co.period = &TimePeriod{start, end}
+	return co
+}
+
+// Rank ranks the other series by co-occurrence with the given anomalies of the target series
+// (or all of its anomalies when none are given), from the most to the least significant.
+func (co *CoOccurrence) Rank(target string, anomalies ...Anomaly) ([]CoOccurrenceResult, error) {
+	if len(anomalies) == 0 {
+		anomalies = co.anomalies[target]
+	}
+	if len(anomalies) == 0 {
+		return nil, errors.New("no target anomalies for " + target)
+	}
+
+	period := co.timePeriod()
+	if period.End <= period.Start {
+		return nil, errors.New("time period must not be empty")
+	}
+
+	results := make([]CoOccurrenceResult, 0, len(co.anomalies))
+	for name, candidates := range co.anomalies {
+		if name != target {
+			results = append(results, co.coOccurrence(name, anomalies, candidates, period))
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].PValue != results[j].PValue {
+			return results[i].PValue < results[j].PValue
+		}
+		if results[i].Matches != results[j].Matches {
+			return results[i].Matches > results[j].Matches
+		}
+		return results[i].Name < results[j].Name
+	})
+	return results, nil
+}
+
+func (co *CoOccurrence) coOccurrence(name string, targets, candidates []Anomaly, period TimePeriod) CoOccurrenceResult {
+	result := CoOccurrenceResult{Name: name, Targets: len(targets), PValue: 1.0}
+
+	// Candidate anomalies are assumed to occur uniformly over the time period
+	rate, averageDuration := float64(len(candidates))/(period.End-period.Start), 0.0
+	for _, candidate := range candidates {
+		start, end := anomalyWindow(candidate)
+		averageDuration += (end - start) / float64(len(candidates))
+	}
+
+	chances := make([]float64, len(targets))
+	for i, target := range targets {
+		start, end := anomalyWindow(target)
+		windowStart, windowEnd := start-co.before, end+co.after
+		chances[i] = 1 - math.Exp(-rate*(windowEnd-windowStart+averageDuration))
+		result.Expected += chances[i]
+
+		closest, found := 0.0, false
+		for _, candidate := range candidates {
+			candidateStart, candidateEnd := anomalyWindow(candidate)
+			if candidateEnd < windowStart || candidateStart > windowEnd {
+				continue
+			}
+			if lag := candidate.Timestamp - target.Timestamp; !found || math.Abs(lag) < math.Abs(closest) {
+				closest, found = lag, true
+			}
+		}
+		if found {
+			result.Matches++
+			result.Lags = append(result.Lags, closest)
+		}
+	}
+
+	if result.Matches > 0 {
+		result.MedianLag = Median(result.Lags)
+		result.Leads = result.MedianLag < 0
+		result.PValue = atLeastProbability(chances, result.Matches)
+	}
+	return result
+}
+
+func (co *CoOccurrence) timePeriod() TimePeriod {
+	if co.period != nil {
+		return *co.period
+	}
+
+	period := TimePeriod{Start: math.Inf(1), End: math.Inf(-1)}
+	for _, anomalies := range co.anomalies {
+		for _, anomaly := range anomalies {
+			start, end := anomalyWindow(anomaly)
+			period.Start, period.End = math.Min(period.Start, start), math.Max(period.End, end)
+		}
+	}
+	return period
+}
+
+// anomalyWindow returns the anomaly time window, falling back to its exact timestamp when the window is not set.
+func anomalyWindow(anomaly Anomaly) (float64, float64) {
+	if anomaly.StartTimestamp == 0 && anomaly.EndTimestamp == 0 {
+		return anomaly.Timestamp, anomaly.Timestamp
+	}
+	return anomaly.GetTimeWindow()
+}
+
+// atLeastProbability returns the probability of at least k successes among independent trials
+// with the given success probabilities (Poisson binomial distribution).
+func atLeastProbability(chances []float64, k int) float64 {
+	distribution := make([]float64, len(chances)+1)
+	distribution[0] = 1
+	for i, chance := range chances {
+		for j := i + 1; j > 0; j-- {
+			distribution[j] = distribution[j]*(1-chance) + distribution[j-1]*chance
+		}
+		distribution[0] *= 1 - chance
+	}
+	return math.Min(SumFloat64s(distribution[k:]), 1.0)
+}
@@ -0,0 +1,63 @@
+package anomalia
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCoOccurrenceRank(t *testing.T) {
+	minute := 60 * 1000.0
+	anomalies := map[string][]Anomaly{
+		"latency": {{Timestamp: 100 * minute}, {Timestamp: 400 * minute}, {Timestamp: 700 * minute}},
+		// Errors spike two minutes before every latency anomaly
+		"errors": {{Timestamp: 98 * minute}, {Timestamp: 398 * minute}, {Timestamp: 698 * minute}},
+		// Disk only matches once by chance
+		"disk": {{Timestamp: 101 * minute}, {Timestamp: 250 * minute}, {Timestamp: 550 * minute}, {Timestamp: 900 * minute}},
+		"cpu":  {{Timestamp: 0}, {Timestamp: 1000 * minute}},
+	}
+
+	results, err := NewCoOccurrence(anomalies).Tolerance(5*60, 5*60).Rank("latency")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+
+	top := results[0]
+	if top.Name != "errors" || top.Matches != 3 || !top.Leads || top.MedianLag != -2*minute {
+		t.Fatalf("expected errors to lead by 2 minutes, got %+v", top)
+	}
+	if top.PValue > 0.01 {
+		t.Fatalf("expected a significant co-occurrence, got %v", top.PValue)
+	}
+	if results[1].Name != "disk" || results[1].Matches != 1 || results[1].PValue < 0.05 {
+		t.Fatalf("expected disk to co-occur by chance, got %+v", results[1])
+	}
+	if results[2].Name != "cpu" || results[2].Matches != 0 || results[2].PValue != 1 {
+		t.Fatalf("expected cpu not to co-occur, got %+v", results[2])
+	}
+}
+
+func TestCoOccurrenceWithSingleTargetAnomaly(t *testing.T) {
+	anomalies := map[string][]Anomaly{
+		"latency": {{Timestamp: 1000}},
+		"errors":  {{Timestamp: 4000, StartTimestamp: 3000, EndTimestamp: 5000}},
+	}
+
+	results, _ := NewCoOccurrence(anomalies).Tolerance(1, 1).TimePeriod(0, 100000).Rank("latency", Anomaly{Timestamp: 2500})
+	if results[0].Matches != 1 || results[0].MedianLag != 1500 || results[0].Leads {
+		t.Fatalf("expected errors to lag by 1500, got %+v", results[0])
+	}
+
+	if _, err := NewCoOccurrence(anomalies).Rank("unknown"); err == nil {
+		t.Fatalf("must fail without target anomalies")
+	}
+}
+
+func TestAtLeastProbability(t *testing.T) {
+	// Two fair coins: P(at least one head) = 0.75
+	if actual := atLeastProbability([]float64{0.5, 0.5}, 1); math.Abs(actual-0.75) > 1e-12 {
+		t.Fatalf("expected 0.75, got %v", actual)
+	}
+}
@@ -0,0 +1,296 @@
+package anomalia
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// ComparisonTest type checker for the statistical test used to compare two time series
+type ComparisonTest int32
+
+const (
+	// MannWhitneyU tests whether one time series tends to have larger values than the other (rank based).
+	MannWhitneyU ComparisonTest = iota
+
+	// KolmogorovSmirnov tests whether both time series values follow the same distribution.
+	KolmogorovSmirnov
+
+	// WelchT tests whether both time series have the same mean without assuming equal variances.
+	WelchT
+)
+
+// Verdict type checker for the outcome of a comparison
+type Verdict int32
+
+const (
+	// Pass means there is no significant difference between the time series.
+	Pass Verdict = iota
+
+	// Marginal means the difference is significant but its effect size is within the tolerance.
+	Marginal
+
+	// Fail means the difference is significant and its effect size exceeds the tolerance.
+	Fail
+)
+
+func (v Verdict) String() string {
+	switch v {
+	case Pass:
+		return "pass"
+	case Marginal:
+		return "marginal"
+	default:
+		return "fail"
+	}
+}
+
+func (t ComparisonTest) String() string {
+	switch t {
+	case MannWhitneyU:
+		return "mann-whitney-u"
+	case KolmogorovSmirnov:
+		return "kolmogorov-smirnov"
+	default:
+		return "welch-t"
+	}
+}
+
+// Comparison holds the canary comparison configuration.
+//
+// It compares the values of a canary time series against a baseline time series (e.g. the rest of the fleet)
+// over the same time period, ignoring the order of the values.
+type Comparison struct {
+	baseline, canary *TimeSeries
+	test             ComparisonTest
+	significance     float64
+	tolerance        float64
+	period           *TimePeriod
+}
+
+// ComparisonResult holds the outcome of a comparison.
+// The effect size depends on the test: the rank-biserial correlation for Mann-Whitney U, the maximal distance
+// between cumulative distributions for Kolmogorov-Smirnov and Cohen's d for Welch's t-test.
+// Except for Kolmogorov-Smirnov, it is positive when the canary values are larger than the baseline ones.
+type ComparisonResult struct {
+	Test           ComparisonTest
+	Statistic      float64
+	PValue         float64
+	EffectSize     float64
+	RelativeChange float64
+	BaselineSize   int
+	CanarySize     int
+	Verdict        Verdict
+}
+
+// NewComparison returns an instance of the comparison between the baseline and the canary time series.
+func NewComparison(baseline, canary *TimeSeries) *Comparison {
+	return &Comparison{
+		baseline:     baseline,
+		canary:       canary,
+		test:         MannWhitneyU,
+		significance: 0.05,
+		tolerance:    0.2,
+	}
+}
+
+// Method sets the statistical test used by Run (defaults to MannWhitneyU).
+func (c *Comparison) Method(test ComparisonTest) *Comparison {
+	c.test = test
+	return c
+}
+
+// Significance sets the p-value below which a difference is significant (defaults to 0.05).
+func (c *Comparison) Significance(alpha float64) *Comparison {
+	c.significance = alpha
+	return c
+}
+
+// Tolerance sets the absolute effect size below which a significant difference is only marginal (defaults to 0.2).
+func (c *Comparison) Tolerance(tolerance float64) *Comparison {
+	c.tolerance = tolerance
+	return c
+}
+
+// TimePeriod restricts both time series to the specified range.
+func (c *Comparison) TimePeriod(start, end float64) *Comparison {
+	c.period = &TimePeriod{start, end}
+	return c
+}
+
+// Run compares the time series using the configured test.
+func (c *Comparison) Run() (*ComparisonResult, error) {
+	baseline, canary, err := c.values()
+	if err != nil {
+		return nil, err
+	}
+	return c.compare(c.test, baseline, canary), nil
+}
+
+// RunAll compares the time series using every test.
+func (c *Comparison) RunAll() ([]ComparisonResult, error) {
+	baseline, canary, err := c.values()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]ComparisonResult, 0, 3)
+	for _, test := range []ComparisonTest{MannWhitneyU, KolmogorovSmirnov, WelchT} {
+		results = append(results, *c.compare(test, baseline, canary))
+	}
+	return results, nil
+}
+
+// WorstVerdict returns the worst verdict among the results.
+func WorstVerdict(results []ComparisonResult) Verdict {
+	verdict := Pass
+	for _, result := range results {
+		if result.Verdict > verdict {
+			verdict = result.Verdict
+		}
+	}
+	return verdict
+}
+
+func (c *Comparison) values() ([]float64, []float64, error) {
+	baseline, canary := c.baseline, c.canary
+	if c.period != nil {
+		baseline, canary = baseline.Crop(c.period.Start, c.period.End), canary.Crop(c.period.Start, c.period.End)
+	}
+	if baseline.Size() < 2 || canary.Size() < 2 {
+		return nil, nil, errors.New("not enough data points")
+	}
+	return baseline.Values, canary.Values, nil
+}
+
+func (c *Comparison) compare(test ComparisonTest, baseline, canary []float64) *ComparisonResult {
+	var result *ComparisonResult
+	switch test {
+	case MannWhitneyU:
+		result = mannWhitneyU(baseline, canary)
+	case KolmogorovSmirnov:
+		result = kolmogorovSmirnov(baseline, canary)
+	case WelchT:
+		result = welchT(baseline, canary)
+	default:
+		panic("unsupported comparison test")
+	}
+
+	result.Test = test
+	result.BaselineSize, result.CanarySize = len(baseline), len(canary)
+	if baselineMedian := Median(baseline); baselineMedian != 0 {
+		result.RelativeChange = (Median(canary) - baselineMedian) / math.Abs(baselineMedian)
+	}
+
+	switch {
+	case result.PValue >= c.significance:
+		result.Verdict = Pass
+	case math.Abs(result.EffectSize) <= c.tolerance:
+		result.Verdict = Marginal
+	default:
+		result.Verdict = Fail
+	}
+	return result
+}
+
+// mannWhitneyU uses the normal approximation with tie and continuity corrections.
+func mannWhitneyU(baseline, canary []float64) *ComparisonResult {
+	n1, n2 := float64(len(baseline)), float64(len(canary))
+	ranks := averageRanks(append(copySlice(baseline), canary...))
+
+	canaryRankSum := SumFloat64s(ranks[len(baseline):])
+	u := canaryRankSum - n2*(n2+1)/2
+
+	// Tie correction
+	n, ties := n1+n2, 0.0
+	sorted := sortedCopy(ranks)
+	for i := 0; i < len(sorted); {
+		j := i
+		for j < len(sorted) && sorted[j] == sorted[i] {
+			j++
+		}
+		t := float64(j - i)
+		ties += t*t*t - t
+		i = j
+	}
+
+	mean := n1 * n2 / 2
+	sigma := math.Sqrt(n1 * n2 / 12 * ((n + 1) - ties/(n*(n-1))))
+	pValue := 1.0
+	if sigma > 0 {
+		z := math.Max(math.Abs(u-mean)-0.5, 0) / sigma
+		pValue = math.Erfc(z / math.Sqrt2)
+	}
+
+	return &ComparisonResult{
+		Statistic:  u,
+		PValue:     pValue,
+		EffectSize: 2*u/(n1*n2) - 1,
+	}
+}
+
+// kolmogorovSmirnov uses the asymptotic Kolmogorov distribution with Stephens' correction.
+func kolmogorovSmirnov(baseline, canary []float64) *ComparisonResult {
+	sortedBaseline, sortedCanary := sortedCopy(baseline), sortedCopy(canary)
+	n1, n2 := float64(len(baseline)), float64(len(canary))
+
+	distance := 0.0
+	i, j := 0, 0
+	for i < len(sortedBaseline) && j < len(sortedCanary) {
+		value := math.Min(sortedBaseline[i], sortedCanary[j])
+		i += sort.Search(len(sortedBaseline)-i, func(k int) bool { return sortedBaseline[i+k] > value })
+		j += sort.Search(len(sortedCanary)-j, func(k int) bool { return sortedCanary[j+k] > value })
+		distance = math.Max(distance, math.Abs(float64(i)/n1-float64(j)/n2))
+	}
+
+	en := math.Sqrt(n1 * n2 / (n1 + n2))
+	return &ComparisonResult{
+		Statistic:  distance,
+		PValue:     kolmogorovSurvival((en + 0.12 + 0.11/en) * distance),
+		EffectSize: distance,
+	}
+}
+
+// kolmogorovSurvival returns the probability that the Kolmogorov distribution exceeds lambda.
+func kolmogorovSurvival(lambda float64) float64 {
+	if lambda < 1e-3 {
+		return 1.0
+	}
+	sum, sign := 0.0, 1.0
+	for k := 1; k <= 100; k++ {
+		term := sign * math.Exp(-2*float64(k*k)*lambda*lambda)
+		sum += term
+		if math.Abs(term) < 1e-12 {
+			break
+		}
+		sign = -sign
+	}
+	return math.Max(math.Min(2*sum, 1.0), 0.0)
+}
+
+// welchT uses the Welch-Satterthwaite degrees of freedom.
+func welchT(baseline, canary []float64) *ComparisonResult {
+	n1, n2 := float64(len(baseline)), float64(len(canary))
+	m1, m2 := Average(baseline), Average(canary)
+	// Unbiased sample variances
+	v1, v2 := Variance(baseline)*n1/(n1-1), Variance(canary)*n2/(n2-1)
+
+	standardError := math.Sqrt(v1/n1 + v2/n2)
+	result := &ComparisonResult{PValue: 1.0}
+	if pooled := math.Sqrt((v1 + v2) / 2); pooled > 0 {
+		result.EffectSize = (m2 - m1) / pooled
+	}
+	if standardError == 0 {
+		if m1 != m2 {
+			result.Statistic, result.PValue = math.Copysign(math.Inf(1), m2-m1), 0.0
+			result.EffectSize = math.Copysign(math.Inf(1), m2-m1)
+		}
+		return result
+	}
+
+	t := (m2 - m1) / standardError
+	degreesOfFreedom := math.Pow(v1/n1+v2/n2, 2) / (v1*v1/(n1*n1*(n1-1)) + v2*v2/(n2*n2*(n2-1)))
+	result.Statistic = t
+	result.PValue = 2 * (1 - StudentTCdf(degreesOfFreedom)(math.Abs(t)))
+	return result
+}
@@ -0,0 +1,89 @@
+package anomalia
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func generateNormalTimeSeries(rng *rand.Rand, size int, mean, stdev float64) *TimeSeries {
+	timestamps, values := make([]float64, size), make([]float64, size)
+	for i := range values {
+		timestamps[i] = float64(i)
+		values[i] = mean + stdev*rng.NormFloat64()
+	}
+	return NewTimeSeries(timestamps, values)
+}
+
+func TestComparisonVerdicts(t *testing.T) {
+	rng := rand.New(rand.NewSource(13))
+	baseline := generateNormalTimeSeries(rng, 200, 100, 10)
+
+	tests := []struct {
+		canary   *TimeSeries
+		expected Verdict
+	}{
+		{generateNormalTimeSeries(rng, 200, 100, 10), Pass},
+		{generateNormalTimeSeries(rng, 200, 115, 10), Fail},
+	}
+	for _, test := range tests {
+		results, err := NewComparison(baseline, test.canary).RunAll()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, result := range results {
+			if result.Verdict != test.expected {
+				t.Fatalf("expected %v, got %+v", test.expected, result)
+			}
+		}
+	}
+
+	// A small shift becomes significant with many data points but stays within the tolerance
+	large := generateNormalTimeSeries(rng, 20000, 100, 10)
+	shifted := generateNormalTimeSeries(rng, 20000, 101, 10)
+	result, _ := NewComparison(large, shifted).Method(WelchT).Run()
+	if result.Verdict != Marginal || result.EffectSize <= 0 || result.RelativeChange <= 0 {
+		t.Fatalf("expected a marginal increase, got %+v", result)
+	}
+}
+
+func TestWelchT(t *testing.T) {
+	baseline := []float64{27.5, 21.0, 19.0, 23.6, 17.0, 17.9, 16.9, 20.1, 21.9, 22.6, 23.1, 19.6, 19.0, 21.7, 21.4}
+	canary := []float64{27.1, 22.0, 20.8, 23.4, 23.4, 23.5, 25.8, 22.0, 24.8, 20.2, 21.9, 22.1, 22.9, 20.5, 24.4}
+
+	result := welchT(baseline, canary)
+	if math.Abs(result.Statistic-2.455356) > 1e-6 || math.Abs(result.PValue-0.021378) > 1e-5 {
+		t.Fatalf("expected t = 2.455356 and p = 0.021378, got %+v", result)
+	}
+}
+
+func TestMannWhitneyU(t *testing.T) {
+	result := mannWhitneyU([]float64{1, 2, 3, 4}, []float64{5, 6, 7, 8})
+	if result.Statistic != 16 || result.EffectSize != 1 {
+		t.Fatalf("expected every canary value to be larger, got %+v", result)
+	}
+
+	result = mannWhitneyU([]float64{1, 2, 3, 4}, []float64{1, 2, 3, 4})
+	if result.Statistic != 8 || result.EffectSize != 0 || result.PValue != 1 {
+		t.Fatalf("expected no difference, got %+v", result)
+	}
+}
+
+func TestKolmogorovSmirnov(t *testing.T) {
+	result := kolmogorovSmirnov([]float64{1, 2, 3, 4}, []float64{3, 4, 5, 6})
+	if result.Statistic != 0.5 {
+		t.Fatalf("expected a distance of 0.5, got %v", result.Statistic)
+	}
+
+	// 1.36 is the critical value at the 5% level
+	if actual := kolmogorovSurvival(1.36); math.Abs(actual-0.0495) > 1e-3 {
+		t.Fatalf("expected 0.0495, got %v", actual)
+	}
+}
+
+func TestComparisonWithNotEnoughDataPoints(t *testing.T) {
+	timeSeries := NewTimeSeries([]float64{0, 1, 2}, []float64{1, 2, 3})
+	if _, err := NewComparison(timeSeries, timeSeries).TimePeriod(5, 10).Run(); err == nil {
+		t.Fatalf("must fail when there are not enough data points in the time period")
+	}
+}
@@ -0,0 +1,162 @@
+package anomalia
+
+import (
+	"errors"
+	"math"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// CorrelationMatrix holds the configuration to correlate many named time series at once.
+type CorrelationMatrix struct {
+	series          map[string]*TimeSeries
+	method          CorrelationMethod
+	options         []float64
+	workers         int
+	useAnomalyScore bool
+	level           float64
+}
+
+// CorrelatedPair holds the correlation result of two named time series.
+type CorrelatedPair struct {
+	First, Second string
+	CorrelationResult
+}
+
+// NewCorrelationMatrix returns an instance of the correlation matrix struct.
+func NewCorrelationMatrix(series map[string]*TimeSeries) *CorrelationMatrix {
+	return &CorrelationMatrix{
+		series:  series,
+		method:  Pearson,
+		workers: runtime.NumCPU(),
+	}
+}
+
+// CorrelationMethod specifies which correlation method to use (defaults to Pearson).
+func (cm *CorrelationMatrix) CorrelationMethod(method CorrelationMethod, options []float64) *CorrelationMatrix {
+	cm.method = method
+	cm.options = options
+	return cm
+}
+
+// Workers sets the maximum number of pairs correlated concurrently (defaults to the number of CPUs).
+func (cm *CorrelationMatrix) Workers(n int) *CorrelationMatrix {
+	cm.workers = n
+	return cm
+}
+
+// UseAnomalyScore tells the matrix to correlate the anomaly scores of the time series.
+func (cm *CorrelationMatrix) UseAnomalyScore(use bool) *CorrelationMatrix {
+	cm.useAnomalyScore = use
+	return cm
+}
+
+// Significance tells the matrix to also compute p-values and confidence intervals at the given level.
+// When 0 (default), only the coefficients are computed.
+func (cm *CorrelationMatrix) Significance(level float64) *CorrelationMatrix {
+	cm.level = level
+	return cm
+}
+
+// Pairs correlates every pair of time series and returns them sorted by decreasing absolute coefficient.
+// Pairs which cannot be correlated (e.g. not enough data points) are left out.
+func (cm *CorrelationMatrix) Pairs() []CorrelatedPair {
+	names := cm.names()
+	var jobs [][2]string
+	for i := range names {
+		for j := i + 1; j < len(names); j++ {
+			jobs = append(jobs, [2]string{names[i], names[j]})
+		}
+	}
+	return cm.run(jobs)
+}
+
+// Rank correlates the named time series with all the others and returns them sorted by decreasing absolute coefficient.
+func (cm *CorrelationMatrix) Rank(name string) ([]CorrelatedPair, error) {
+	if _, ok := cm.series[name]; !ok {
+		return nil, errors.New("unknown time series " + name)
+	}
+
+	var jobs [][2]string
+	for _, other := range cm.names() {
+		if other != name {
+			jobs = append(jobs, [2]string{name, other})
+		}
+	}
+	return cm.run(jobs), nil
+}
+
+func (cm *CorrelationMatrix) names() []string {
+	return sortedNames(cm.series)
+}
+
+func (cm *CorrelationMatrix) run(jobs [][2]string) []CorrelatedPair {
+	var (
+		wg      sync.WaitGroup
+		sem     = make(semaphore, maxInt(cm.workers, 1))
+		results = make([]*CorrelatedPair, len(jobs))
+	)
+
+	wg.Add(len(jobs))
+	for idx, job := range jobs {
+		sem.Lock()
+		go func(idx int, job [2]string) {
+			defer wg.Done()
+			defer sem.Unlock()
+			results[idx] = cm.correlate(job[0], job[1])
+		}(idx, job)
+	}
+	wg.Wait()
+
+	pairs := make([]CorrelatedPair, 0, len(results))
+	for _, pair := range results {
+		if pair != nil {
+			pairs = append(pairs, *pair)
+		}
+	}
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return math.Abs(pairs[i].Coefficient) > math.Abs(pairs[j].Coefficient)
+	})
+	return pairs
+}
+
+// correlate correlates copies of the two time series, since some algorithms modify them in place.
+// Methods which need series of the same size get them aligned first.
+func (cm *CorrelationMatrix) correlate(first, second string) *CorrelatedPair {
+	current, target := alignedCopies(cm.series[first], cm.series[second], cm.method)
+
+	correlator := NewCorrelator(current, target).
+		CorrelationMethod(cm.method, cm.options).
+		UseAnomalyScore(cm.useAnomalyScore)
+
+	var (
+		result CorrelationResult
+		err    error
+	)
+	if cm.level > 0 {
+		if err = correlator.prepare(); err == nil {
+			result = correlator.algorithm.Test(cm.level)
+		}
+	} else {
+		result, err = correlator.result()
+	}
+	if err != nil {
+		return nil
+	}
+	return &CorrelatedPair{First: first, Second: second, CorrelationResult: result}
+}
+
+// alignedCopies returns copies of both time series, aligned on the same timestamps
+// unless the method handles time series of different sizes (XCorr and DynamicTimeWarping).
+func alignedCopies(current, target *TimeSeries, method CorrelationMethod) (*TimeSeries, *TimeSeries) {
+	current, target = copyTimeSeries(current), copyTimeSeries(target)
+	if method != XCorr && method != DynamicTimeWarping {
+		current.Align(target)
+	}
+	return current, target
+}
+
+func copyTimeSeries(ts *TimeSeries) *TimeSeries {
+	return NewTimeSeries(copySlice(ts.Timestamps), copySlice(ts.Values))
+}
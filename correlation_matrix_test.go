@@ -0,0 +1,76 @@
+package anomalia
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func generateNamedTimeSeries() map[string]*TimeSeries {
+	rng := rand.New(rand.NewSource(11))
+	series := map[string]*TimeSeries{}
+	for _, name := range []string{"cpu", "load", "free", "noise", "lagged"} {
+		series[name] = NewTimeSeries(make([]float64, 200), make([]float64, 200))
+	}
+	for i := 0; i < 200; i++ {
+		base := math.Sin(float64(i) / 8)
+		timestamp := float64(i * 1000)
+		for name, value := range map[string]float64{
+			"cpu":    base + 0.05*rng.NormFloat64(),
+			"load":   base + 0.1*rng.NormFloat64(),
+			"free":   -base + 0.05*rng.NormFloat64(),
+			"noise":  rng.NormFloat64(),
+			"lagged": math.Sin(float64(i-3)/8) + 0.05*rng.NormFloat64(),
+		} {
+			series[name].Timestamps[i] = timestamp
+			series[name].Values[i] = value + 10
+		}
+	}
+	return series
+}
+
+func TestCorrelationMatrixPairs(t *testing.T) {
+	pairs := NewCorrelationMatrix(generateNamedTimeSeries()).Workers(2).Pairs()
+	if len(pairs) != 10 {
+		t.Fatalf("expected 10 pairs, got %d", len(pairs))
+	}
+	for i := 1; i < len(pairs); i++ {
+		if math.Abs(pairs[i].Coefficient) > math.Abs(pairs[i-1].Coefficient) {
+			t.Fatalf("pairs must be sorted by decreasing absolute coefficient")
+		}
+	}
+
+	last := pairs[len(pairs)-1]
+	if last.First != "noise" && last.Second != "noise" {
+		t.Fatalf("expected the noise to be the least correlated, got %s and %s", last.First, last.Second)
+	}
+}
+
+func TestCorrelationMatrixRank(t *testing.T) {
+	matrix := NewCorrelationMatrix(generateNamedTimeSeries()).CorrelationMethod(XCorr, []float64{10, 0.01})
+	ranking, err := matrix.Rank("cpu")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ranking) != 4 || ranking[3].Second != "noise" {
+		t.Fatalf("expected noise to be ranked last, got %+v", ranking)
+	}
+	for _, pair := range ranking {
+		if pair.Second == "lagged" && pair.Shift == 0 {
+			t.Fatalf("expected a shift for the lagged series")
+		}
+	}
+
+	if _, err := matrix.Rank("unknown"); err == nil {
+		t.Fatalf("must fail for an unknown time series")
+	}
+}
+
+func TestCorrelationMatrixSignificance(t *testing.T) {
+	ranking, _ := NewCorrelationMatrix(generateNamedTimeSeries()).Significance(0.95).Rank("cpu")
+	for _, pair := range ranking {
+		if pair.Second == "free" && (pair.Coefficient > -0.9 || pair.PValue > 0.01) {
+			t.Fatalf("expected a significant negative correlation, got %+v", pair)
+		}
+	}
+}
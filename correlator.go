@@ -1,8 +1,14 @@
 package anomalia
 
+import (
+	"math"
+	"math/rand"
+)
+
 // CorrelationAlgorithm base interface for correlation algorithms.
 type CorrelationAlgorithm interface {
 	Run() float64
+	Test(level float64) CorrelationResult
 	sanityCheck() error
 }
 
@@ -16,11 +22,23 @@ const (
 	SpearmanRank
 	// Pearson represents the Pearson Correlation algorithm.
 	Pearson
+	// KendallTau represents the Kendall Tau-b Correlation algorithm.
+	KendallTau
+	// DynamicTimeWarping represents the Dynamic Time Warping similarity algorithm.
+	DynamicTimeWarping
+	// KSGMutualInformation represents the Mutual Information algorithm using the KSG estimator.
+	KSGMutualInformation
+	// BinnedMutualInformation represents the Mutual Information algorithm using the binned estimator.
+	BinnedMutualInformation
+	// DCor represents the Distance Correlation algorithm.
+	DCor
 )
 
 // Correlator holds the correlator configuration.
 type Correlator struct {
 	current, target *TimeSeries
+	method          CorrelationMethod
+	options         []float64
 	algorithm       CorrelationAlgorithm
 	useAnomalyScore bool
 }
@@ -36,9 +54,11 @@ func NewCorrelator(current, target *TimeSeries) *Correlator {
 	}
 }
 
-// CorrelationMethod specifies which correlation method to use (XCross or SpearmanRank).
+// CorrelationMethod specifies which correlation method to use (defaults to XCorr).
+// The algorithm is only built when running, so that it uses the cropped time series or their anomaly scores.
 func (c *Correlator) CorrelationMethod(method CorrelationMethod, options []float64) *Correlator {
-	c.algorithm = c.getCorrelationAlgorithmByMethod(method, options)
+	c.method = method
+	c.options = options
 	return c
 }
 
@@ -57,30 +77,79 @@ func (c *Correlator) UseAnomalyScore(use bool) *Correlator {
 
 // Run runs the correlator.
 func (c *Correlator) Run() float64 {
-	if err := c.algorithm.sanityCheck(); err != nil {
+	if err := c.prepare(); err != nil {
 		panic(err)
 	}
+	return c.algorithm.Run()
+}
 
-	if c.useAnomalyScore {
-		c.current = getAnomalyScores(NewDetector(c.current))
-		c.target = getAnomalyScores(NewDetector(c.target))
+// Test runs the correlator and returns the coefficient along with its p-value, sample size
+// and confidence interval at the given level (e.g. 0.95).
+func (c *Correlator) Test(level float64) CorrelationResult {
+	if err := c.prepare(); err != nil {
+		panic(err)
 	}
+	return c.algorithm.Test(level)
+}
 
-	return c.algorithm.Run()
+// result runs the correlator and returns the correlation result, including the shift for cross correlation.
+func (c *Correlator) result() (CorrelationResult, error) {
+	if err := c.prepare(); err != nil {
+		return CorrelationResult{}, err
+	}
+	if xcorr, ok := c.algorithm.(*CrossCorrelation); ok {
+		return xcorr.GetCorrelationResult(), nil
+	}
+	return CorrelationResult{Coefficient: c.algorithm.Run()}, nil
+}
+
+// prepare builds the correlation algorithm, on the anomaly scores when requested.
+// The time series of the correlator are left untouched, so that it can run again.
+func (c *Correlator) prepare() error {
+	c.algorithm = c.getCorrelationAlgorithmByMethod(c.current, c.target, c.method, c.options)
+	if err := c.algorithm.sanityCheck(); err != nil {
+		return err
+	}
+
+	if c.useAnomalyScore {
+		current := getAnomalyScores(NewDetector(c.current))
+		target := getAnomalyScores(NewDetector(c.target))
+		c.algorithm = c.getCorrelationAlgorithmByMethod(current, target, c.method, c.options)
+	}
+	return nil
 }
 
-func (c *Correlator) getCorrelationAlgorithmByMethod(method CorrelationMethod, options []float64) CorrelationAlgorithm {
+func (c *Correlator) getCorrelationAlgorithmByMethod(current, target *TimeSeries, method CorrelationMethod, options []float64) CorrelationAlgorithm {
 	var algorithm CorrelationAlgorithm
 	switch method {
 	case XCorr:
-		algorithm = NewCrossCorrelation(c.current, c.target)
+		algorithm = NewCrossCorrelation(current, target)
 		if options != nil && len(options) > 0 {
 			algorithm = algorithm.(*CrossCorrelation).MaxShift(options[0]).Impact(options[1])
 		}
 	case SpearmanRank:
-		algorithm = NewSpearmanCorrelation(c.current, c.target)
+		algorithm = NewSpearmanCorrelation(current, target)
 	case Pearson:
-		algorithm = NewPearsonCorrelation(c.current, c.target)
+		algorithm = NewPearsonCorrelation(current, target)
+	case KendallTau:
+		algorithm = NewKendallCorrelation(current, target)
+	case DynamicTimeWarping:
+		algorithm = NewDTW(current, target)
+		if len(options) > 0 {
+			algorithm = algorithm.(*DTW).Window(int(options[0]))
+		}
+	case KSGMutualInformation:
+		algorithm = NewMutualInformation(current, target)
+		if len(options) > 0 {
+			algorithm = algorithm.(*MutualInformation).Neighbors(int(options[0]))
+		}
+	case BinnedMutualInformation:
+		algorithm = NewMutualInformation(current, target).Estimator(Binned)
+		if len(options) > 0 {
+			algorithm = algorithm.(*MutualInformation).Bins(int(options[0]))
+		}
+	case DCor:
+		algorithm = NewDistanceCorrelation(current, target)
 	default:
 		panic("unsupported correlation method/algorithm")
 	}
@@ -92,5 +161,50 @@ func getAnomalyScores(detector *Detector) *TimeSeries {
 	if scoreList == nil {
 		panic("failed to calculate anomaly scores")
 	}
-	return &TimeSeries{scoreList.Timestamps, scoreList.Scores}
+	return scoreList.ToTimeSeries()
+}
+
+// fisherCorrelationResult builds the result of a coefficient whose p-value comes from the t-distribution
+// and whose confidence interval comes from the Fisher z-transformation.
+func fisherCorrelationResult(r float64, n int, level, varianceFactor float64, offset int) CorrelationResult {
+	result := CorrelationResult{Coefficient: r, PValue: correlationPValue(r, n), SampleSize: n, Level: level}
+	result.Lower, result.Upper = fisherInterval(r, n, level, varianceFactor, offset)
+	return result
+}
+
+// correlationPValue returns the two-sided p-value of the hypothesis that there is no correlation.
+func correlationPValue(r float64, n int) float64 {
+	if n < 3 {
+		return 1.0
+	}
+	if math.Abs(r) >= 1 {
+		return 0.0
+	}
+	degreesOfFreedom := float64(n - 2)
+	t := math.Abs(r) * math.Sqrt(degreesOfFreedom/(1-r*r))
+	return 2 * (1 - StudentTCdf(degreesOfFreedom)(t))
+}
+
+// fisherInterval returns the confidence interval of the coefficient using the Fisher z-transformation,
+// where the standard error is sqrt(varianceFactor / (n - offset)).
+func fisherInterval(r float64, n int, level, varianceFactor float64, offset int) (float64, float64) {
+	if n <= offset {
+		return -1.0, 1.0
+	}
+	z := math.Atanh(r)
+	margin := Quantile(0, 1)(0.5+level/2) * math.Sqrt(varianceFactor/float64(n-offset))
+	return math.Tanh(z - margin), math.Tanh(z + margin)
+}
+
+// permutationPValue returns the p-value of the observed statistic against the statistic of shuffled target values,
+// counting the observed statistic itself so that it is never 0.
+func permutationPValue(observed float64, target []float64, resamples int, rng *rand.Rand, statistic func([]float64) float64) float64 {
+	shuffled, exceeding := copySlice(target), 0
+	for i := 0; i < resamples; i++ {
+		rng.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+		if statistic(shuffled) >= observed {
+			exceeding++
+		}
+	}
+	return float64(exceeding+1) / float64(resamples+1)
 }
@@ -1,6 +1,10 @@
 package anomalia
 
-import "testing"
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
 
 func TestRunCorrelatorWithXCorr(t *testing.T) {
 	timeSeriesA := NewTimeSeries([]float64{0, 1, 2, 3, 4, 5, 6, 7}, []float64{1, 2, -2, 4, 2, 3, 1, 0})
@@ -38,6 +42,36 @@ func TestRunCorrelatorWithPearson(t *testing.T) {
 	}
 }
 
+func TestRunCorrelatorWithKendallTau(t *testing.T) {
+	timeSeriesA := NewTimeSeries([]float64{0, 1, 2, 3, 4, 5, 6, 7}, []float64{1, 2, -2, 4, 2, 3, 1, 0})
+	timeSeriesB := NewTimeSeries([]float64{0, 1, 2, 3, 4, 5, 6, 7}, []float64{1, 2, -2, 4, 2, 3, 1, 0})
+
+	coefficient := NewCorrelator(timeSeriesA, timeSeriesB).CorrelationMethod(KendallTau, nil).Run()
+	if coefficient != 1.0 {
+		t.Fatalf("incorrect coefficient: time series are exactly the same")
+	}
+}
+
+func TestRunCorrelatorWithDynamicTimeWarping(t *testing.T) {
+	timeSeriesA := NewTimeSeries([]float64{0, 1, 2, 3, 4, 5, 6, 7}, []float64{1, 2, -2, 4, 2, 3, 1, 0})
+	timeSeriesB := NewTimeSeries([]float64{0, 1, 2, 3, 4, 5, 6, 7}, []float64{1, 2, -2, 4, 2, 3, 1, 0})
+
+	coefficient := NewCorrelator(timeSeriesA, timeSeriesB).CorrelationMethod(DynamicTimeWarping, []float64{2}).Run()
+	if coefficient != 1.0 {
+		t.Fatalf("incorrect coefficient: time series are exactly the same")
+	}
+}
+
+func TestRunCorrelatorWithNonLinearMethods(t *testing.T) {
+	load, latency := generateSaturatedTimeSeries(200, 1)
+	for _, method := range []CorrelationMethod{KSGMutualInformation, BinnedMutualInformation, DCor} {
+		coefficient := NewCorrelator(load, latency).CorrelationMethod(method, nil).Run()
+		if coefficient < 0.3 || coefficient > 1 {
+			t.Fatalf("expected a strong dependency with method %d, got %v", method, coefficient)
+		}
+	}
+}
+
 func TestRunPearsonCorrelationWhenTimeSeriesHaveDifferentSizes(t *testing.T) {
 	timeSeriesA := NewTimeSeries([]float64{0, 1, 2, 3, 4}, []float64{0, 3.2, 5.5, 7.1, 8.9})
 	timeSeriesB := NewTimeSeries([]float64{0, 1, 2, 3, 4, 5}, []float64{-0.5, 1, 2.5, 4.1, 4.6, -1})
@@ -79,3 +113,44 @@ func TestRunSpearmanCorrelationWhenTimeSeriesHaveDifferentSizes(t *testing.T) {
 
 	NewCorrelator(timeSeriesA, timeSeriesB).CorrelationMethod(SpearmanRank, nil).Run()
 }
+
+func TestCorrelatorSignificance(t *testing.T) {
+	timeSeriesA := NewTimeSeries([]float64{0, 1, 2, 3, 4, 5, 6, 7}, []float64{1, 2, -2, 4, 2, 3, 1, 0})
+	timeSeriesB := NewTimeSeries([]float64{0, 1, 2, 3, 4, 5, 6, 7}, []float64{1, 2, -2, 4, 2, 3, 1, 0})
+
+	result := NewCorrelator(timeSeriesA, timeSeriesB).CorrelationMethod(KendallTau, nil).Test(0.95)
+	if result.Coefficient != 1.0 || result.PValue > 0.05 || result.SampleSize != 8 {
+		t.Fatalf("expected a significant perfect correlation, got %+v", result)
+	}
+}
+
+func TestCorrelatorTimePeriodAppliesToAlgorithm(t *testing.T) {
+	timeSeriesA := NewTimeSeries([]float64{0, 1, 2, 3, 4, 5, 6, 7}, []float64{1, 2, 3, 4, 5, 3, 1, 0})
+	timeSeriesB := NewTimeSeries([]float64{0, 1, 2, 3, 4, 5, 6, 7}, []float64{1, 2, 3, 4, 5, 9, 8, 9})
+
+	coefficient := NewCorrelator(timeSeriesA, timeSeriesB).CorrelationMethod(Pearson, nil).TimePeriod(0, 4).Run()
+	if math.Abs(coefficient-1.0) > 1e-12 {
+		t.Fatalf("expected a perfect correlation within the time period, got %v", coefficient)
+	}
+}
+
+func TestCorrelatorWithAnomalyScoreRunsTwice(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	timestamps, valuesA, valuesB := make([]float64, 1000), make([]float64, 1000), make([]float64, 1000)
+	for i := range timestamps {
+		timestamps[i] = float64(i)
+		valuesA[i] = rng.NormFloat64()
+		valuesB[i] = valuesA[i] + rng.NormFloat64()
+	}
+	timeSeriesA, timeSeriesB := NewTimeSeries(timestamps, valuesA), NewTimeSeries(timestamps, valuesB)
+
+	correlator := NewCorrelator(timeSeriesA, timeSeriesB).CorrelationMethod(Pearson, nil).UseAnomalyScore(true)
+	first := correlator.Run()
+	second := correlator.Run()
+	if first != second {
+		t.Fatalf("running twice must return the same coefficient, got %v and %v", first, second)
+	}
+	if correlator.current != timeSeriesA || correlator.target != timeSeriesB {
+		t.Fatalf("running must not replace the time series with their anomaly scores")
+	}
+}
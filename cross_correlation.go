@@ -3,36 +3,59 @@ package anomalia
 import (
 	"errors"
 	"math"
+	"math/rand"
+	"sort"
 )
 
 // CrossCorrelation holds Cross Correlation algorithm parameters and settings.
 // It is calculated by multiplying and summing the current and target time series together.
 //
 // This implementation uses normalized time series which makes scoring easy to understand:
-// 	- The higher the coefficient, the higher the correlation is.
-// 	- The maximum value of the correlation coefficient is 1.
-//	- The minimum value of the correlation coefficient is -1.
-//	- Two time series are exactly the same when their correlation coefficient is equal to 1.
+//   - The higher the coefficient, the higher the correlation is.
+//   - The maximum value of the correlation coefficient is 1.
+//   - The minimum value of the correlation coefficient is -1.
+//   - Two time series are exactly the same when their correlation coefficient is equal to 1.
 type CrossCorrelation struct {
 	current, target *TimeSeries
 	maxShift        float64
 	impact          float64
+	resamples       int
+	seed            int64
 }
 
+// fftCorrelationThreshold is the number of products above which the correlogram is computed using FFT.
+const fftCorrelationThreshold = 1 << 16
+
 // CorrelationResult holds detected correlation result.
+// The shift is signed: it is positive when the target lags behind the current time series,
+// and negative when the target leads. The correlogram is only set by the cross correlation,
+// and the significance fields (p-value, sample size and confidence interval) are only set by Test.
 type CorrelationResult struct {
 	Shift              float64
 	Coefficient        float64
 	ShiftedCoefficient float64
+	Correlogram        []CorrelogramPoint
+	PValue             float64
+	SampleSize         int
+	Lower, Upper       float64
+	Level              float64
+}
+
+// CorrelogramPoint holds the correlation coefficient at a given lag (in time units).
+type CorrelogramPoint struct {
+	Lag         float64
+	Coefficient float64
 }
 
 // NewCrossCorrelation returns an instance of the cross correlation struct.
 func NewCrossCorrelation(current *TimeSeries, target *TimeSeries) *CrossCorrelation {
 	return &CrossCorrelation{
-		current:  current,
-		target:   target,
-		maxShift: 60 * 1000,
-		impact:   0.05,
+		current:   current,
+		target:    target,
+		maxShift:  60 * 1000,
+		impact:    0.05,
+		resamples: 200,
+		seed:      1,
 	}
 }
 
@@ -48,9 +71,22 @@ func (cc *CrossCorrelation) Impact(impact float64) *CrossCorrelation {
 	return cc
 }
 
+// Resamples sets the number of permutations and bootstrap samples used by Test (defaults to 200).
+func (cc *CrossCorrelation) Resamples(n int) *CrossCorrelation {
+	cc.resamples = n
+	return cc
+}
+
+// Seed sets the seed of the random source used by Test (defaults to 1).
+func (cc *CrossCorrelation) Seed(seed int64) *CrossCorrelation {
+	cc.seed = seed
+	return cc
+}
+
 // GetCorrelationResult runs the cross correlation algorithm.
 func (cc *CrossCorrelation) GetCorrelationResult() CorrelationResult {
-	return cc.detectCorrelation()
+	result, _ := cc.detectCorrelation()
+	return result
 }
 
 // Run runs the cross correlation algorithm and returns only the coefficient.
@@ -58,6 +94,36 @@ func (cc *CrossCorrelation) Run() float64 {
 	return cc.GetCorrelationResult().Coefficient
 }
 
+// Test runs the cross correlation algorithm along with its significance.
+// The p-value comes from a permutation test which shuffles the target values and compares the best
+// coefficients over all shifts, and the confidence interval from bootstrapping the pairs at the best shift.
+func (cc *CrossCorrelation) Test(level float64) CorrelationResult {
+	result, delay := cc.detectCorrelation()
+	currentValues, targetValues := cc.current.Values, cc.target.Values
+	rng := rand.New(rand.NewSource(cc.seed))
+
+	result.PValue = permutationPValue(result.Coefficient, targetValues, cc.resamples, rng, func(shuffled []float64) float64 {
+		permuted, _ := cc.correlate(currentValues, shuffled)
+		return permuted.Coefficient
+	})
+
+	products := crossProducts(currentValues, targetValues, delay)
+	replicates := make([]float64, cc.resamples)
+	for i := range replicates {
+		for range products {
+			replicates[i] += products[rng.Intn(len(products))]
+		}
+	}
+
+	result.SampleSize = cc.current.Size()
+	result.Level = level
+	if len(replicates) > 0 {
+		result.Lower = Percentile(replicates, 50*(1-level))
+		result.Upper = Percentile(replicates, 50*(1+level))
+	}
+	return result
+}
+
 func (cc *CrossCorrelation) sanityCheck() error {
 	if cc.current.Size() < 2 || cc.target.Size() < 2 {
 		return errors.New("not enough data points")
@@ -65,90 +131,113 @@ func (cc *CrossCorrelation) sanityCheck() error {
 	return nil
 }
 
-func (cc *CrossCorrelation) detectCorrelation() CorrelationResult {
+func (cc *CrossCorrelation) detectCorrelation() (CorrelationResult, int) {
 	cc.current, cc.target = cc.current.Normalize(), cc.target.Normalize()
 	cc.current.Align(cc.target)
+	return cc.correlate(cc.current.Values, cc.target.Values)
+}
 
-	correlations := make([][]float64, 0)
-	shiftedCorrelations := make([]float64, 0)
-
-	currentValues, targetValues := cc.current.Values, cc.target.Values
-	currentAvg, targetAvg := cc.current.Average(), cc.target.Average()
-	currentStdev, targetStdev := cc.current.Stdev(), cc.target.Stdev()
-
-	n := cc.current.Size()
-	denom := currentStdev * targetStdev * float64(n)
-	allowedShiftStep := findMaxAllowedShift(cc.current.Timestamps, cc.maxShift)
-
-	var shiftLowerBound, shiftUpperBound int
-	if allowedShiftStep != -1 {
-		shiftLowerBound = -allowedShiftStep
-		shiftUpperBound = allowedShiftStep
-	} else {
-		shiftLowerBound = 0
-		shiftUpperBound = 1
-	}
-
-	for delay := shiftLowerBound; delay < shiftUpperBound; delay++ {
-		_delay := math.Abs(cc.current.Timestamps[AbsInt(delay)] - cc.current.Timestamps[0])
-		sum := 0.0
-		for i := 0; i < n; i++ {
-			j := i + delay
-			if j < 0 || j >= n {
-				continue
-			} else {
-				sum += (currentValues[i] - currentAvg) * (targetValues[j] - targetAvg)
-			}
+// correlate returns the correlation result over all allowed shifts along with the best delay (in points).
+// A positive delay pairs the current values with later target values, meaning that the target lags behind.
+func (cc *CrossCorrelation) correlate(currentValues, targetValues []float64) (CorrelationResult, int) {
+	timestamps := cc.current.Timestamps
+	maxDelay := minInt(findMaxAllowedShift(timestamps, cc.maxShift), len(currentValues)-1)
+	coefficients := crossCorrelogram(currentValues, targetValues, maxDelay)
+
+	correlogram := make([]CorrelogramPoint, len(coefficients))
+	best, maxShiftedCorrelation := 0, math.Inf(-1)
+	for idx, r := range coefficients {
+		delay := idx - maxDelay
+		lag := timestamps[AbsInt(delay)] - timestamps[0]
+		if delay < 0 {
+			lag = -lag
 		}
+		correlogram[idx] = CorrelogramPoint{Lag: lag, Coefficient: r}
 
-		// Calculate correlation coefficient
-		r := sum
-		if denom != 0 {
-			r = sum / denom
+		if r > coefficients[best] {
+			best = idx
 		}
-		correlations = append(correlations, []float64{_delay, r})
 
 		// Take into account the maximal shift
 		if cc.maxShift > 0 {
-			r *= 1 + _delay/float64(cc.maxShift)*cc.impact
+			r *= 1 + math.Abs(lag)/cc.maxShift*cc.impact
 		}
-		shiftedCorrelations = append(shiftedCorrelations, r)
+		maxShiftedCorrelation = math.Max(maxShiftedCorrelation, r)
 	}
 
-	maxCorrelation := findMaxCorrelation(correlations)
-	_, maxShiftedCorrelation := minMax(shiftedCorrelations)
 	return CorrelationResult{
-		Shift:              maxCorrelation[0],
-		Coefficient:        maxCorrelation[1],
+		Shift:              correlogram[best].Lag,
+		Coefficient:        correlogram[best].Coefficient,
 		ShiftedCoefficient: maxShiftedCorrelation,
-	}
+		Correlogram:        correlogram,
+	}, best - maxDelay
 }
 
-func findMaxAllowedShift(timestamps []float64, target float64) int {
-	initialTimestamp := timestamps[0]
-	residualTimestamps := mapSlice(timestamps, func(ts float64) float64 {
-		return ts - initialTimestamp
-	})
-	// Find the first element in timestamps whose value is bigger than target.
-	pos := -1
-	lowerBound, upperBound := 0, len(residualTimestamps)
-	for lowerBound < upperBound {
-		pos = int(lowerBound + (upperBound-lowerBound)/2)
-		if timestamps[pos] > target {
-			upperBound = pos
-		} else {
-			lowerBound = pos + 1
+// crossCorrelogram returns the correlation coefficients for every delay in [-maxDelay, maxDelay],
+// so that the coefficient of delay d is at index d + maxDelay.
+// Long time series are correlated with an FFT-based convolution instead of a sum per delay.
+func crossCorrelogram(currentValues, targetValues []float64, maxDelay int) []float64 {
+	n := len(currentValues)
+	currentAvg, targetAvg := Average(currentValues), Average(targetValues)
+	denom := Stdev(currentValues) * Stdev(targetValues) * float64(n)
+	if denom == 0 {
+		denom = 1
+	}
+
+	coefficients := make([]float64, 2*maxDelay+1)
+	if n*len(coefficients) <= fftCorrelationThreshold {
+		for idx := range coefficients {
+			delay := idx - maxDelay
+			for i := maxInt(0, -delay); i < minInt(n, n-delay); i++ {
+				coefficients[idx] += (currentValues[i] - currentAvg) * (targetValues[i+delay] - targetAvg)
+			}
+			coefficients[idx] /= denom
 		}
+		return coefficients
 	}
-	return pos
+
+	// Convolving the reversed current values with the target values yields the sum of delay d at index d + n - 1
+	reversed, centered := make([]float64, n), make([]float64, n)
+	for i := range currentValues {
+		reversed[n-1-i] = currentValues[i] - currentAvg
+		centered[i] = targetValues[i] - targetAvg
+	}
+	sums := convolve(reversed, centered)
+	for idx := range coefficients {
+		coefficients[idx] = sums[idx-maxDelay+n-1] / denom
+	}
+	return coefficients
 }
 
-func findMaxCorrelation(data [][]float64) []float64 {
-	max := data[0]
-	for _, slice := range data {
-		if slice[1] > max[1] {
-			max = slice
+// crossProducts returns the normalized products of the centered values paired at the given delay,
+// which sum up to the correlation coefficient.
+func crossProducts(currentValues, targetValues []float64, delay int) []float64 {
+	currentAvg, targetAvg := Average(currentValues), Average(targetValues)
+	n := len(currentValues)
+	denom := Stdev(currentValues) * Stdev(targetValues) * float64(n)
+
+	products := make([]float64, 0, n)
+	for i := 0; i < n; i++ {
+		j := i + delay
+		if j < 0 || j >= n {
+			continue
+		}
+		product := (currentValues[i] - currentAvg) * (targetValues[j] - targetAvg)
+		if denom != 0 {
+			product /= denom
 		}
+		products = append(products, product)
 	}
-	return max
+	return products
+}
+
+// findMaxAllowedShift returns the largest number of steps whose residual timestamp (relative to the first one)
+// does not exceed the target shift. The timestamps must be sorted.
+func findMaxAllowedShift(timestamps []float64, target float64) int {
+	initialTimestamp := timestamps[0]
+	// Find the first residual timestamp which is bigger than target
+	pos := sort.Search(len(timestamps), func(i int) bool {
+		return timestamps[i]-initialTimestamp > target
+	})
+	return maxInt(pos-1, 0)
 }
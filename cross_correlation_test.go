@@ -1,6 +1,10 @@
 package anomalia
 
-import "testing"
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
 
 func TestNewCrossCorrelation(t *testing.T) {
 	timeSeriesA := NewTimeSeries([]float64{0, 1, 2, 3, 4, 5, 6, 7}, []float64{1, 2, -2, 4, 2, 3, 1, 0})
@@ -35,3 +39,88 @@ func TestCorrelationWhenTimeSeriesExactlyTheSame(t *testing.T) {
 		t.Fatalf("incorrect coefficient: time series are exactly the same")
 	}
 }
+
+func TestCrossCorrelationSignificance(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+	timestamps := make([]float64, 100)
+	current, target, noise := make([]float64, 100), make([]float64, 100), make([]float64, 100)
+	for i := range timestamps {
+		timestamps[i] = float64(i * 1000)
+		current[i] = 10 + math.Sin(float64(i)/5) + 0.1*rng.NormFloat64()
+		target[i] = 10 + math.Sin(float64(i)/5) + 0.1*rng.NormFloat64()
+		noise[i] = 10 + rng.NormFloat64()
+	}
+
+	result := NewCrossCorrelation(NewTimeSeries(timestamps, current), NewTimeSeries(timestamps, target)).MaxShift(3).Test(0.95)
+	if result.PValue > 0.01 {
+		t.Fatalf("expected a significant correlation, got p-value %v", result.PValue)
+	}
+	if result.Lower > result.Coefficient || result.Upper < result.Coefficient {
+		t.Fatalf("interval [%v, %v] must contain %v", result.Lower, result.Upper, result.Coefficient)
+	}
+
+	result = NewCrossCorrelation(NewTimeSeries(timestamps, copySlice(current)), NewTimeSeries(timestamps, noise)).MaxShift(3).Test(0.95)
+	if result.PValue < 0.05 {
+		t.Fatalf("expected an insignificant correlation, got p-value %v", result.PValue)
+	}
+}
+
+func TestCrossCorrelationSignedShift(t *testing.T) {
+	rng := rand.New(rand.NewSource(9))
+	timestamps, current, target := make([]float64, 100), make([]float64, 100), make([]float64, 100)
+	for i := range timestamps {
+		timestamps[i] = 1e6 + float64(i*1000)
+		current[i] = 10 + rng.NormFloat64()
+	}
+	// The target lags 3 seconds behind the current time series
+	for i := range target {
+		target[i] = current[maxInt(i-3, 0)]
+	}
+
+	result := NewCrossCorrelation(NewTimeSeries(timestamps, current), NewTimeSeries(copySlice(timestamps), target)).
+		MaxShift(5).
+		GetCorrelationResult()
+	if result.Shift != 3000 {
+		t.Fatalf("expected a shift of 3000, got %v", result.Shift)
+	}
+	if len(result.Correlogram) != 11 || result.Correlogram[0].Lag != -5000 || result.Correlogram[10].Lag != 5000 {
+		t.Fatalf("expected a correlogram from -5000 to 5000, got %v", result.Correlogram)
+	}
+
+	result = NewCrossCorrelation(NewTimeSeries(copySlice(timestamps), target), NewTimeSeries(copySlice(timestamps), current)).
+		MaxShift(5).
+		GetCorrelationResult()
+	if result.Shift != -3000 {
+		t.Fatalf("expected a shift of -3000, got %v", result.Shift)
+	}
+}
+
+func TestCrossCorrelogramWithFFT(t *testing.T) {
+	rng := rand.New(rand.NewSource(10))
+	current, target := make([]float64, 3000), make([]float64, 3000)
+	for i := range current {
+		current[i], target[i] = rng.NormFloat64(), rng.NormFloat64()
+	}
+
+	// 3000 * 41 products are computed using FFT
+	fast := crossCorrelogram(current, target, 20)
+	for idx, coefficient := range fast {
+		delay, sum := idx-20, 0.0
+		for _, product := range crossProducts(current, target, delay) {
+			sum += product
+		}
+		if math.Abs(coefficient-sum) > 1e-9 {
+			t.Fatalf("expected %v at delay %d, got %v", sum, delay, coefficient)
+		}
+	}
+}
+
+func TestFindMaxAllowedShift(t *testing.T) {
+	timestamps := []float64{5000, 6000, 7000, 8000, 9000}
+	if steps := findMaxAllowedShift(timestamps, 2500); steps != 2 {
+		t.Fatalf("expected 2 steps, got %d", steps)
+	}
+	if steps := findMaxAllowedShift(timestamps, 0); steps != 0 {
+		t.Fatalf("expected 0 steps, got %d", steps)
+	}
+}
@@ -0,0 +1,47 @@
+package anomalia
+
+import "math"
+
+// Decomposition holds the components of a decomposed time series.
+//
+// The strength metrics are in the [0, 1] range where values close to 1 indicate a strong trend or seasonality.
+// For the used formulas, check: https://otexts.com/fpp2/seasonal-strength.html
+type Decomposition struct {
+	Periodicity      int
+	Trend            *TimeSeries
+	Seasonal         *TimeSeries
+	Residual         *TimeSeries
+	TrendStrength    float64
+	SeasonalStrength float64
+}
+
+// computeStrengths calculates both trend and seasonal strengths.
+// Multiplicative components are log transformed so that they can be added together.
+func (d *Decomposition) computeStrengths(multiplicative bool) {
+	transform := func(values []float64) []float64 {
+		if !multiplicative {
+			return values
+		}
+		return mapSlice(values, math.Log)
+	}
+
+	trend := transform(d.Trend.Values)
+	seasonal := transform(d.Seasonal.Values)
+	residual := transform(d.Residual.Values)
+
+	d.TrendStrength = componentStrength(trend, residual)
+	d.SeasonalStrength = componentStrength(seasonal, residual)
+}
+
+// componentStrength returns max(0, 1 - Var(residual) / Var(component + residual)).
+func componentStrength(component, residual []float64) float64 {
+	combined := mapSliceWithIndex(component, func(i int, value float64) float64 {
+		return value + residual[i]
+	})
+
+	variance := Variance(combined)
+	if variance == 0 {
+		return 0.0
+	}
+	return math.Max(0, 1-Variance(residual)/variance)
+}
@@ -53,7 +53,7 @@ func (d *Detector) GetAnomalies(scoreList *ScoreList) []Anomaly {
 
 	// Locate the exact anomaly timestamp within each interval
 	for _, interval := range intervals {
-		intervalSeries := d.timeSeries.Crop(interval.Start, interval.Start)
+		intervalSeries := d.timeSeries.Crop(interval.Start, interval.End)
 		refinedScoreList := NewEma().Run(intervalSeries)
 		maxRefinedScore := refinedScoreList.Max()
 
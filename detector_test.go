@@ -43,3 +43,29 @@ func TestGetAnomaliesInTestData(t *testing.T) {
 		t.Fatalf("there are exactly 2 anomalies")
 	}
 }
+
+func TestGetAnomaliesRefinesTimestampWithinInterval(t *testing.T) {
+	timestamps, values, scores := make([]float64, 20), make([]float64, 20), make([]float64, 20)
+	for i := range timestamps {
+		timestamps[i] = float64(i + 1)
+		values[i] = 1
+	}
+	values[12] = 10
+	for i := 10; i < 15; i++ {
+		scores[i] = 5
+	}
+
+	detector := NewDetector(NewTimeSeries(timestamps, values)).Threshold(2.0)
+	anomalies := detector.GetAnomalies(&ScoreList{timestamps, scores})
+	if len(anomalies) != 1 {
+		t.Fatalf("expected exactly one anomaly, got %d", len(anomalies))
+	}
+
+	anomaly := anomalies[0]
+	if anomaly.StartTimestamp != 11 || anomaly.EndTimestamp != 15 {
+		t.Fatalf("expected interval [11, 15], got [%v, %v]", anomaly.StartTimestamp, anomaly.EndTimestamp)
+	}
+	if anomaly.Timestamp != 13 || anomaly.Value != 10 {
+		t.Fatalf("expected the anomaly at the spike (13, 10), got (%v, %v)", anomaly.Timestamp, anomaly.Value)
+	}
+}
@@ -0,0 +1,115 @@
+package anomalia
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+)
+
+// DistanceCorrelation holds the distance correlation algorithm configuration.
+// It is zero if and only if the time series are independent, so it also detects non-linear
+// and non-monotonic dependencies.
+//
+// The distance correlator returns a value from 0 to 1, where:
+//   - 1 = the time series are linearly related
+//   - 0 = the time series are independent.
+//
+// For details, check: https://en.wikipedia.org/wiki/Distance_correlation
+type DistanceCorrelation struct {
+	current, target *TimeSeries
+	resamples       int
+	seed            int64
+}
+
+// NewDistanceCorrelation returns an instance of the distance correlation struct.
+func NewDistanceCorrelation(current, target *TimeSeries) *DistanceCorrelation {
+	return &DistanceCorrelation{
+		current:   current,
+		target:    target,
+		resamples: 200,
+		seed:      1,
+	}
+}
+
+// Resamples sets the number of permutations used by Test (defaults to 200).
+func (dc *DistanceCorrelation) Resamples(n int) *DistanceCorrelation {
+	dc.resamples = n
+	return dc
+}
+
+// Seed sets the seed of the random source used by Test (defaults to 1).
+func (dc *DistanceCorrelation) Seed(seed int64) *DistanceCorrelation {
+	dc.seed = seed
+	return dc
+}
+
+// Run runs the distance correlation on the current and target time series.
+func (dc *DistanceCorrelation) Run() float64 {
+	return distanceCorrelation(dc.current.Values, dc.target.Values)
+}
+
+// Test runs the distance correlation along with its significance.
+// The p-value comes from a permutation test which shuffles the target values.
+// There is no confidence interval for the coefficient, so Lower and Upper are NaN.
+// Each permutation recomputes the distance matrices, so Test runs in O(resamples·n²).
+func (dc *DistanceCorrelation) Test(level float64) CorrelationResult {
+	current, target := dc.current.Values, dc.target.Values
+	coefficient := distanceCorrelation(current, target)
+	pValue := permutationPValue(coefficient, target, dc.resamples, rand.New(rand.NewSource(dc.seed)), func(shuffled []float64) float64 {
+		return distanceCorrelation(current, shuffled)
+	})
+
+	return CorrelationResult{
+		Coefficient: coefficient,
+		PValue:      pValue,
+		SampleSize:  len(current),
+		Lower:       math.NaN(),
+		Upper:       math.NaN(),
+		Level:       level,
+	}
+}
+
+func (dc *DistanceCorrelation) sanityCheck() error {
+	if dc.current.Size() < 2 || dc.current.Size() != dc.target.Size() {
+		return errors.New("current and/or target series have an invalid dimension")
+	}
+	return nil
+}
+
+// distanceCorrelation computes the double centered distances on the fly,
+// so that it only needs linear memory.
+func distanceCorrelation(x, y []float64) float64 {
+	xRowMeans, xMean := distanceMeans(x)
+	yRowMeans, yMean := distanceMeans(y)
+
+	var covariance, xVariance, yVariance float64
+	for i := range x {
+		for j := range x {
+			a := math.Abs(x[i]-x[j]) - xRowMeans[i] - xRowMeans[j] + xMean
+			b := math.Abs(y[i]-y[j]) - yRowMeans[i] - yRowMeans[j] + yMean
+			covariance += a * b
+			xVariance += a * a
+			yVariance += b * b
+		}
+	}
+
+	denom := math.Sqrt(xVariance * yVariance)
+	if denom == 0 {
+		return 0.0
+	}
+	return math.Sqrt(math.Max(covariance, 0) / denom)
+}
+
+// distanceMeans returns the row means and the grand mean of the distance matrix of the values.
+func distanceMeans(values []float64) ([]float64, float64) {
+	rowMeans := make([]float64, len(values))
+	grandMean := 0.0
+	for i := range values {
+		for j := range values {
+			rowMeans[i] += math.Abs(values[i] - values[j])
+		}
+		rowMeans[i] /= float64(len(values))
+		grandMean += rowMeans[i]
+	}
+	return rowMeans, grandMean / float64(len(values))
+}
@@ -0,0 +1,29 @@
+package anomalia
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDistanceCorrelationWithLinearRelation(t *testing.T) {
+	timeSeriesA := NewTimeSeries([]float64{0, 1, 2, 3, 4, 5, 6, 7}, []float64{1, 2, -2, 4, 2, 3, 1, 0})
+	timeSeriesB := NewTimeSeries([]float64{0, 1, 2, 3, 4, 5, 6, 7}, []float64{-2, -4, 4, -8, -4, -6, -2, 0})
+
+	if coefficient := NewDistanceCorrelation(timeSeriesA, timeSeriesB).Run(); math.Abs(coefficient-1) > 1e-12 {
+		t.Fatalf("expected 1, got %v", coefficient)
+	}
+}
+
+func TestDistanceCorrelationDetectsNonMonotonicDependency(t *testing.T) {
+	load, latency := generateSaturatedTimeSeries(300, 1)
+	result := NewDistanceCorrelation(load, latency).Resamples(50).Test(0.95)
+	if result.Coefficient < 0.3 || result.PValue > 0.05 {
+		t.Fatalf("expected a significant dependency, got %+v", result)
+	}
+
+	_, independent := generateSaturatedTimeSeries(300, 2)
+	result = NewDistanceCorrelation(load, independent).Resamples(50).Test(0.95)
+	if result.PValue < 0.05 {
+		t.Fatalf("expected an insignificant dependency, got %+v", result)
+	}
+}
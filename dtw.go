@@ -0,0 +1,325 @@
+package anomalia
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+)
+
+// DTW holds the Dynamic Time Warping algorithm configuration.
+//
+// Unlike cross correlation which only handles a rigid shift, DTW aligns time series that are stretched
+// or compressed in time by finding the warping path with the lowest cumulative squared distance.
+// The similarity is 1 / (1 + rms) where rms is the root mean squared distance along the warping path,
+// so that it has a value between 0 and 1 where 1 means the time series are identical (once warped).
+type DTW struct {
+	current, target *TimeSeries
+	window          int
+	zNormalize      bool
+	resamples       int
+	seed            int64
+}
+
+// DTWResult holds the DTW distance and similarity along with the warping path.
+// Each step of the path pairs an index of the current time series with an index of the target time series.
+type DTWResult struct {
+	Distance   float64
+	Similarity float64
+	Path       [][2]int
+}
+
+// DTWMatch holds the subsequence of a time series which is the closest to a query.
+type DTWMatch struct {
+	Index     int
+	Timestamp float64
+	Distance  float64
+}
+
+// NewDTW returns an instance of the DTW struct.
+func NewDTW(current, target *TimeSeries) *DTW {
+	return &DTW{
+		current:    current,
+		target:     target,
+		window:     -1,
+		zNormalize: true,
+		resamples:  200,
+		seed:       1,
+	}
+}
+
+// Window sets the radius (in points) of the Sakoe-Chiba band constraining the warping path.
+// A negative window (default) leaves the path unconstrained.
+func (d *DTW) Window(window int) *DTW {
+	d.window = window
+	return d
+}
+
+// ZNormalize tells the algorithm whether to z-normalize both time series first (defaults to true).
+func (d *DTW) ZNormalize(normalize bool) *DTW {
+	d.zNormalize = normalize
+	return d
+}
+
+// Resamples sets the number of permutations used by Test (defaults to 200).
+func (d *DTW) Resamples(n int) *DTW {
+	d.resamples = n
+	return d
+}
+
+// Seed sets the seed of the random source used by Test (defaults to 1).
+func (d *DTW) Seed(seed int64) *DTW {
+	d.seed = seed
+	return d
+}
+
+// GetResult runs the DTW algorithm and returns the distance, similarity and warping path.
+// The distance and similarity are NaN when either time series has less than two points.
+func (d *DTW) GetResult() DTWResult {
+	if err := d.sanityCheck(); err != nil {
+		return DTWResult{Distance: math.NaN(), Similarity: math.NaN()}
+	}
+	current, target := d.values()
+	cost := dtwMatrix(current, target, d.window)
+	path := cost.warpingPath()
+	distance := cost.at(len(current), len(target))
+	return DTWResult{
+		Distance:   math.Sqrt(distance),
+		Similarity: dtwSimilarity(distance, len(path)),
+		Path:       path,
+	}
+}
+
+// Run runs the DTW algorithm and returns only the similarity.
+func (d *DTW) Run() float64 {
+	return d.GetResult().Similarity
+}
+
+// Test runs the DTW algorithm along with its significance.
+// The p-value comes from a permutation test which shuffles the target values.
+// There is no confidence interval for the similarity, so Lower and Upper are NaN.
+func (d *DTW) Test(level float64) CorrelationResult {
+	result := d.GetResult()
+	if math.IsNaN(result.Similarity) {
+		return CorrelationResult{Coefficient: math.NaN(), PValue: math.NaN(), Lower: math.NaN(), Upper: math.NaN(), Level: level}
+	}
+	current, target := d.values()
+	rng := rand.New(rand.NewSource(d.seed))
+
+	pValue := permutationPValue(result.Similarity, target, d.resamples, rng, func(shuffled []float64) float64 {
+		return dtwSimilarity(dtwDistance(current, shuffled, d.window, math.Inf(1)))
+	})
+
+	return CorrelationResult{
+		Coefficient: result.Similarity,
+		PValue:      pValue,
+		SampleSize:  minInt(len(current), len(target)),
+		Lower:       math.NaN(),
+		Upper:       math.NaN(),
+		Level:       level,
+	}
+}
+
+func (d *DTW) sanityCheck() error {
+	if d.current.Size() < 2 || d.target.Size() < 2 {
+		return errors.New("not enough data points")
+	}
+	return nil
+}
+
+func (d *DTW) values() ([]float64, []float64) {
+	if d.zNormalize {
+		return ZNormalize(d.current.Values), ZNormalize(d.target.Values)
+	}
+	return d.current.Values, d.target.Values
+}
+
+// LBKeogh returns the LB_Keogh lower bound of the DTW distance between a query and a candidate of the same size.
+// It measures how far the candidate goes outside the envelope of the query within the Sakoe-Chiba band,
+// and is much cheaper to compute than the DTW distance itself. It returns the trivial bound 0 when the sizes differ.
+func LBKeogh(query, candidate []float64, window int) float64 {
+	if len(query) != len(candidate) {
+		return 0.0
+	}
+	upper, lower := envelope(query, window)
+	return math.Sqrt(lbKeogh(upper, lower, candidate, math.Inf(1)))
+}
+
+// SearchDTW returns the subsequence of the time series which is the closest to the query under DTW
+// (both z-normalized), using the LB_Keogh lower bound and early abandoning to skip most candidates.
+func SearchDTW(query []float64, timeSeries *TimeSeries, window int) (DTWMatch, error) {
+	m, n := len(query), timeSeries.Size()
+	if m < 2 || n < m {
+		return DTWMatch{}, errors.New("query must have at least two points and fit in the time series")
+	}
+
+	query = ZNormalize(query)
+	upper, lower := envelope(query, window)
+
+	best := DTWMatch{Index: -1, Distance: math.Inf(1)}
+	candidate := make([]float64, m)
+	for start := 0; start+m <= n; start++ {
+		subsequence := timeSeries.Values[start : start+m]
+		mean, stdev := Average(subsequence), Stdev(subsequence)
+		for i, value := range subsequence {
+			candidate[i] = value - mean
+			if stdev >= 1e-8 {
+				candidate[i] /= stdev
+			}
+		}
+
+		if lbKeogh(upper, lower, candidate, best.Distance) >= best.Distance {
+			continue
+		}
+		if distance, _ := dtwDistance(query, candidate, window, best.Distance); distance < best.Distance {
+			best = DTWMatch{Index: start, Timestamp: timeSeries.Timestamps[start], Distance: distance}
+		}
+	}
+
+	best.Distance = math.Sqrt(best.Distance)
+	return best, nil
+}
+
+// dtwCost holds the cumulative squared cost within the Sakoe-Chiba band only,
+// where row i covers the columns from offsets[i] to offsets[i] + len(rows[i]) - 1.
+type dtwCost struct {
+	rows    [][]float64
+	offsets []int
+}
+
+// dtwMatrix returns the cumulative squared cost, where cost.at(i, j) aligns the first i and j values.
+func dtwMatrix(a, b []float64, window int) dtwCost {
+	n, m := len(a), len(b)
+	window = bandWidth(n, m, window)
+
+	cost := dtwCost{rows: make([][]float64, n+1), offsets: make([]int, n+1)}
+	for i := 1; i <= n; i++ {
+		lo, hi := maxInt(1, i-window), minInt(m, i+window)
+		cost.offsets[i] = lo
+		cost.rows[i] = make([]float64, maxInt(0, hi-lo+1))
+		for j := lo; j <= hi; j++ {
+			delta := a[i-1] - b[j-1]
+			cost.rows[i][j-lo] = delta*delta + math.Min(cost.at(i-1, j-1), math.Min(cost.at(i-1, j), cost.at(i, j-1)))
+		}
+	}
+	return cost
+}
+
+// at returns the cumulative cost of aligning the first i and j values, which is +Inf outside the band.
+func (c dtwCost) at(i, j int) float64 {
+	if i == 0 || j == 0 {
+		if i == 0 && j == 0 {
+			return 0
+		}
+		return math.Inf(1)
+	}
+	if k := j - c.offsets[i]; k >= 0 && k < len(c.rows[i]) {
+		return c.rows[i][k]
+	}
+	return math.Inf(1)
+}
+
+// warpingPath backtracks the cost from the end to the start of both time series.
+func (c dtwCost) warpingPath() [][2]int {
+	i := len(c.rows) - 1
+	j := c.offsets[i] + len(c.rows[i]) - 1
+	path := [][2]int{{i - 1, j - 1}}
+	for i > 1 || j > 1 {
+		switch {
+		case i == 1:
+			j--
+		case j == 1:
+			i--
+		case c.at(i-1, j-1) <= c.at(i-1, j) && c.at(i-1, j-1) <= c.at(i, j-1):
+			i, j = i-1, j-1
+		case c.at(i-1, j) <= c.at(i, j-1):
+			i--
+		default:
+			j--
+		}
+		path = append(path, [2]int{i - 1, j - 1})
+	}
+
+	for left, right := 0, len(path)-1; left < right; left, right = left+1, right-1 {
+		path[left], path[right] = path[right], path[left]
+	}
+	return path
+}
+
+// dtwDistance returns the cumulative squared cost along with the length of the warping path using two rows only.
+// The path length follows the same steps as warpingPath, so that it matches the length of the backtracked path.
+// It abandons early and returns +Inf as soon as the cost cannot be lower than bestSoFar.
+func dtwDistance(a, b []float64, window int, bestSoFar float64) (float64, int) {
+	n, m := len(a), len(b)
+	window = bandWidth(n, m, window)
+
+	previous, current := make([]float64, m+1), make([]float64, m+1)
+	previousLength, currentLength := make([]int, m+1), make([]int, m+1)
+	for j := range previous {
+		previous[j] = math.Inf(1)
+	}
+	previous[0] = 0
+
+	for i := 1; i <= n; i++ {
+		for j := range current {
+			current[j] = math.Inf(1)
+		}
+		rowMin := math.Inf(1)
+		for j := maxInt(1, i-window); j <= minInt(m, i+window); j++ {
+			diagonal, up, left := previous[j-1], previous[j], current[j-1]
+			delta := a[i-1] - b[j-1]
+			switch {
+			case diagonal <= up && diagonal <= left:
+				current[j], currentLength[j] = delta*delta+diagonal, previousLength[j-1]+1
+			case up <= left:
+				current[j], currentLength[j] = delta*delta+up, previousLength[j]+1
+			default:
+				current[j], currentLength[j] = delta*delta+left, currentLength[j-1]+1
+			}
+			rowMin = math.Min(rowMin, current[j])
+		}
+		if rowMin >= bestSoFar {
+			return math.Inf(1), 0
+		}
+		previous, current = current, previous
+		previousLength, currentLength = currentLength, previousLength
+	}
+	return previous[m], previousLength[m]
+}
+
+// envelope returns the running maximum and minimum of the values within the window.
+func envelope(values []float64, window int) ([]float64, []float64) {
+	window = bandWidth(len(values), len(values), window)
+	upper, lower := make([]float64, len(values)), make([]float64, len(values))
+	for i := range values {
+		lower[i], upper[i] = minMax(values[maxInt(0, i-window):minInt(len(values), i+window+1)])
+	}
+	return upper, lower
+}
+
+// lbKeogh returns the squared LB_Keogh lower bound, abandoning as soon as it reaches bestSoFar.
+func lbKeogh(upper, lower, candidate []float64, bestSoFar float64) float64 {
+	sum := 0.0
+	for i, value := range candidate {
+		if value > upper[i] {
+			sum += (value - upper[i]) * (value - upper[i])
+		} else if value < lower[i] {
+			sum += (value - lower[i]) * (value - lower[i])
+		}
+		if sum >= bestSoFar {
+			break
+		}
+	}
+	return sum
+}
+
+// bandWidth returns the Sakoe-Chiba band radius, wide enough for the path to reach the end of both series.
+func bandWidth(n, m, window int) int {
+	if window < 0 {
+		return maxInt(n, m)
+	}
+	return maxInt(window, AbsInt(n-m))
+}
+
+func dtwSimilarity(distance float64, pathLength int) float64 {
+	return 1 / (1 + math.Sqrt(distance/float64(pathLength)))
+}
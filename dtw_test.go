@@ -0,0 +1,121 @@
+package anomalia
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestDTWWithStretchedTimeSeries(t *testing.T) {
+	current, target := generatePeriodicTimeSeries(100, 50), generatePeriodicTimeSeries(150, 75)
+
+	result := NewDTW(current, target).GetResult()
+	if result.Similarity < 0.9 {
+		t.Fatalf("expected stretched time series to be similar, got %v", result.Similarity)
+	}
+
+	first, last := result.Path[0], result.Path[len(result.Path)-1]
+	if first != [2]int{0, 0} || last != [2]int{99, 149} {
+		t.Fatalf("path must go from the start to the end of both series, got %v to %v", first, last)
+	}
+	for i := 1; i < len(result.Path); i++ {
+		di, dj := result.Path[i][0]-result.Path[i-1][0], result.Path[i][1]-result.Path[i-1][1]
+		if di < 0 || dj < 0 || di > 1 || dj > 1 || di+dj == 0 {
+			t.Fatalf("path must be continuous and monotonic at step %d", i)
+		}
+	}
+}
+
+func TestDTWWithNotEnoughDataPoints(t *testing.T) {
+	empty := NewTimeSeries([]float64{}, []float64{})
+	for _, dtw := range []*DTW{NewDTW(empty, empty), NewDTW(generatePeriodicTimeSeries(10, 5), NewTimeSeries([]float64{1}, []float64{1}))} {
+		if result := dtw.GetResult(); !math.IsNaN(result.Similarity) || !math.IsNaN(result.Distance) || result.Path != nil {
+			t.Fatalf("expected NaN result without path, got %v", result)
+		}
+		if similarity := dtw.Run(); !math.IsNaN(similarity) {
+			t.Fatalf("expected NaN similarity, got %v", similarity)
+		}
+		if result := dtw.Test(0.95); !math.IsNaN(result.Coefficient) || !math.IsNaN(result.PValue) {
+			t.Fatalf("expected NaN coefficient and p-value, got %v", result)
+		}
+	}
+}
+
+func TestDTWWindowConstrainsPath(t *testing.T) {
+	current, target := generatePeriodicTimeSeries(100, 50), generatePeriodicTimeSeries(100, 40)
+
+	unconstrained := NewDTW(current, target).GetResult()
+	constrained := NewDTW(current, target).Window(3).GetResult()
+	if constrained.Distance < unconstrained.Distance {
+		t.Fatalf("constrained distance %v must not be lower than %v", constrained.Distance, unconstrained.Distance)
+	}
+	for _, step := range constrained.Path {
+		if AbsInt(step[0]-step[1]) > 3 {
+			t.Fatalf("path step %v goes outside the band", step)
+		}
+	}
+}
+
+func TestDTWDistanceMatchesWarpingPath(t *testing.T) {
+	current, target := generatePeriodicTimeSeries(100, 50), generatePeriodicTimeSeries(150, 60)
+	for _, window := range []int{-1, 0, 5, 60} {
+		result := NewDTW(current, target).Window(window).ZNormalize(false).GetResult()
+		distance, length := dtwDistance(current.Values, target.Values, window, math.Inf(1))
+		if math.Abs(math.Sqrt(distance)-result.Distance) > 1e-9 || length != len(result.Path) {
+			t.Fatalf("window %d: expected distance %v over %d steps, got %v over %d steps",
+				window, result.Distance, len(result.Path), math.Sqrt(distance), length)
+		}
+	}
+}
+
+func TestLBKeoghIsALowerBound(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	for trial := 0; trial < 20; trial++ {
+		query, candidate := make([]float64, 50), make([]float64, 50)
+		for i := range query {
+			query[i], candidate[i] = rng.NormFloat64(), rng.NormFloat64()
+		}
+		bound := LBKeogh(query, candidate, 5)
+		squared, _ := dtwDistance(query, candidate, 5, math.Inf(1))
+		distance := math.Sqrt(squared)
+		if bound > distance+1e-12 {
+			t.Fatalf("lower bound %v exceeds distance %v", bound, distance)
+		}
+	}
+}
+
+func TestSearchDTW(t *testing.T) {
+	rng := rand.New(rand.NewSource(4))
+	timeSeries := NewTimeSeries(make([]float64, 500), make([]float64, 500))
+	for i := range timeSeries.Values {
+		timeSeries.Timestamps[i] = float64(i)
+		timeSeries.Values[i] = rng.NormFloat64()
+	}
+
+	query := generatePeriodicTimeSeries(40, 40).Values
+	for i := 0; i < 50; i++ {
+		// Plant a stretched version of the query
+		timeSeries.Values[300+i] = 5 * math.Sin(2*math.Pi*float64(i)/50)
+	}
+
+	match, err := SearchDTW(query, timeSeries, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs(match.Timestamp-300) > 10 {
+		t.Fatalf("expected a match around 300, got %v", match.Timestamp)
+	}
+
+	if _, err := SearchDTW(query, generatePeriodicTimeSeries(10, 10), 10); err == nil {
+		t.Fatalf("must fail when the query does not fit in the time series")
+	}
+}
+
+func TestDTWSignificance(t *testing.T) {
+	current, target := generatePeriodicTimeSeries(60, 30), generatePeriodicTimeSeries(80, 40)
+
+	result := NewDTW(current, target).Window(20).Resamples(50).Test(0.95)
+	if result.PValue > 0.05 || result.SampleSize != 60 {
+		t.Fatalf("expected a significant similarity over 60 points, got %+v", result)
+	}
+}
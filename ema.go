@@ -1,6 +1,9 @@
 package anomalia
 
-import "math"
+import (
+	"errors"
+	"math"
+)
 
 // ExponentialMovingAverage holds the algorithm configuration.
 // It uses the value's deviation from the exponential moving average
@@ -57,3 +60,64 @@ func computeScoresInLagWindow(data []float64, value, smoothingFactor float64) fl
 	ema := Ema(data, smoothingFactor)[len(data)-1]
 	return math.Abs(value - ema)
 }
+
+// SimpleExponentialSmoothing holds the simple exponential smoothing forecaster configuration.
+// The smoothing factor is applied to the whole time series, and the forecast is the last smoothed level.
+type SimpleExponentialSmoothing struct {
+	smoothingFactor float64
+	level           float64
+	forecastState
+}
+
+// NewSimpleExponentialSmoothing returns SimpleExponentialSmoothing instance
+func NewSimpleExponentialSmoothing() *SimpleExponentialSmoothing {
+	return &SimpleExponentialSmoothing{smoothingFactor: 0.2}
+}
+
+// SmoothingFactor sets the smoothing factor.
+func (ses *SimpleExponentialSmoothing) SmoothingFactor(factor float64) *SimpleExponentialSmoothing {
+	ses.smoothingFactor = factor
+	return ses
+}
+
+// Fit fits the simple exponential smoothing model on the time series.
+func (ses *SimpleExponentialSmoothing) Fit(timeSeries *TimeSeries) error {
+	if timeSeries.Size() < 2 {
+		return errors.New("not enough data points")
+	}
+
+	smoothed := Ema(timeSeries.Values, ses.smoothingFactor)
+	fitted := make([]float64, len(smoothed))
+	fitted[0] = math.NaN()
+	copy(fitted[1:], smoothed[:len(smoothed)-1])
+
+	ses.level = smoothed[len(smoothed)-1]
+	ses.fit(timeSeries, fitted)
+	return nil
+}
+
+// Predict returns the forecast of the next horizon points.
+func (ses *SimpleExponentialSmoothing) Predict(horizon int) (*TimeSeries, error) {
+	if err := checkHorizon(horizon); err != nil {
+		return nil, err
+	}
+	return ses.predict(ses.points(horizon))
+}
+
+// PredictInterval returns the forecast of the next horizon points with their prediction interval.
+func (ses *SimpleExponentialSmoothing) PredictInterval(horizon int, level float64) (*Forecast, error) {
+	if err := checkHorizon(horizon); err != nil {
+		return nil, err
+	}
+	return ses.predictInterval(ses.points(horizon), level, func(h int) float64 {
+		return 1 + float64(h-1)*ses.smoothingFactor*ses.smoothingFactor
+	})
+}
+
+func (ses *SimpleExponentialSmoothing) points(horizon int) []float64 {
+	points := make([]float64, horizon)
+	for i := range points {
+		points[i] = ses.level
+	}
+	return points
+}
@@ -0,0 +1,141 @@
+package anomalia
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// fft computes the discrete Fourier transform of the input.
+// Power of two lengths use the radix-2 algorithm, other lengths use Bluestein's algorithm.
+func fft(input []complex128) []complex128 {
+	n := len(input)
+	if n <= 1 {
+		return append([]complex128(nil), input...)
+	}
+	if n&(n-1) == 0 {
+		output := append([]complex128(nil), input...)
+		radix2(output, false)
+		return output
+	}
+	return bluestein(input)
+}
+
+// ifft computes the inverse discrete Fourier transform of the input.
+func ifft(input []complex128) []complex128 {
+	n := len(input)
+	conjugated := make([]complex128, n)
+	for i, c := range input {
+		conjugated[i] = cmplx.Conj(c)
+	}
+	output := fft(conjugated)
+	for i, c := range output {
+		output[i] = cmplx.Conj(c) / complex(float64(n), 0)
+	}
+	return output
+}
+
+// realFFT computes the discrete Fourier transform of real valued input.
+func realFFT(input []float64) []complex128 {
+	return fft(toComplex(input, len(input)))
+}
+
+// convolve returns the linear convolution of both inputs.
+func convolve(a, b []float64) []float64 {
+	size := len(a) + len(b) - 1
+	n := nextPowerOfTwo(size)
+	fa, fb := fft(toComplex(a, n)), fft(toComplex(b, n))
+	for i := range fa {
+		fa[i] *= fb[i]
+	}
+	product := ifft(fa)
+
+	output := make([]float64, size)
+	for i := range output {
+		output[i] = real(product[i])
+	}
+	return output
+}
+
+func radix2(data []complex128, inverse bool) {
+	n := len(data)
+
+	// Bit reversal permutation
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			data[i], data[j] = data[j], data[i]
+		}
+	}
+
+	sign := -1.0
+	if inverse {
+		sign = 1.0
+	}
+	for size := 2; size <= n; size <<= 1 {
+		step := cmplx.Rect(1, sign*2*math.Pi/float64(size))
+		for start := 0; start < n; start += size {
+			w := complex(1, 0)
+			for k := 0; k < size/2; k++ {
+				even, odd := data[start+k], data[start+k+size/2]*w
+				data[start+k] = even + odd
+				data[start+k+size/2] = even - odd
+				w *= step
+			}
+		}
+	}
+}
+
+func bluestein(input []complex128) []complex128 {
+	n := len(input)
+	m := nextPowerOfTwo(2*n - 1)
+
+	chirp := make([]complex128, n)
+	for k := range chirp {
+		angle := math.Pi * float64((k*k)%(2*n)) / float64(n)
+		chirp[k] = cmplx.Rect(1, -angle)
+	}
+
+	a := make([]complex128, m)
+	b := make([]complex128, m)
+	for k := 0; k < n; k++ {
+		a[k] = input[k] * chirp[k]
+	}
+	b[0] = cmplx.Conj(chirp[0])
+	for k := 1; k < n; k++ {
+		b[k] = cmplx.Conj(chirp[k])
+		b[m-k] = cmplx.Conj(chirp[k])
+	}
+
+	radix2(a, false)
+	radix2(b, false)
+	for i := range a {
+		a[i] *= b[i]
+	}
+	radix2(a, true)
+
+	output := make([]complex128, n)
+	for k := range output {
+		output[k] = a[k] * chirp[k] / complex(float64(m), 0)
+	}
+	return output
+}
+
+func toComplex(input []float64, size int) []complex128 {
+	output := make([]complex128, size)
+	for i, value := range input {
+		output[i] = complex(value, 0)
+	}
+	return output
+}
+
+func nextPowerOfTwo(n int) int {
+	p := 1
+	for p < n {
+		p <<= 1
+	}
+	return p
+}
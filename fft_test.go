@@ -0,0 +1,44 @@
+package anomalia
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+func TestFFTMatchesNaiveDFT(t *testing.T) {
+	for _, n := range []int{8, 12} {
+		input := make([]complex128, n)
+		for i := range input {
+			input[i] = complex(math.Sin(float64(i))+float64(i%3), 0)
+		}
+
+		output := fft(input)
+		for k := 0; k < n; k++ {
+			expected := complex(0, 0)
+			for j := 0; j < n; j++ {
+				expected += input[j] * cmplx.Rect(1, -2*math.Pi*float64(j*k)/float64(n))
+			}
+			if cmplx.Abs(output[k]-expected) > 1e-9 {
+				t.Fatalf("expected %v, got %v (n=%d, k=%d)", expected, output[k], n, k)
+			}
+		}
+
+		restored := ifft(output)
+		for i := range input {
+			if cmplx.Abs(restored[i]-input[i]) > 1e-9 {
+				t.Fatalf("inverse transform must restore the input")
+			}
+		}
+	}
+}
+
+func TestConvolve(t *testing.T) {
+	actual := convolve([]float64{1, 2, 3}, []float64{0, 1, 0.5})
+	expected := []float64{0, 1, 2.5, 4, 1.5}
+	for i := range expected {
+		if math.Abs(actual[i]-expected[i]) > 1e-9 {
+			t.Fatalf("expected %v, got %v", expected, actual)
+		}
+	}
+}
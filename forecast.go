@@ -0,0 +1,156 @@
+package anomalia
+
+import (
+	"errors"
+	"math"
+)
+
+// Forecaster is the base interface of all forecasting models
+type Forecaster interface {
+	Fit(*TimeSeries) error
+	Predict(horizon int) (*TimeSeries, error)
+	PredictInterval(horizon int, level float64) (*Forecast, error)
+	fittedValues() []float64
+}
+
+// Forecast holds predicted values with their prediction interval.
+type Forecast struct {
+	Timestamps []float64
+	Values     []float64
+	Lower      []float64
+	Upper      []float64
+	Level      float64
+}
+
+// forecastState holds the state shared by fitted forecasting models.
+type forecastState struct {
+	lastTimestamp float64
+	interval      float64
+	fitted        []float64
+	sigma         float64
+	isFitted      bool
+}
+
+// fit stores the one-step ahead in-sample predictions and derives the residuals standard deviation.
+// Predictions are NaN for the warm-up points that cannot be predicted.
+func (fs *forecastState) fit(timeSeries *TimeSeries, fitted []float64) {
+	fs.lastTimestamp = timeSeries.LastestTimestamp()
+	fs.interval = timeSeries.samplingInterval()
+	if fs.interval == 0 {
+		fs.interval = 1
+	}
+	fs.fitted = fitted
+
+	sum, count := 0.0, 0
+	for i, prediction := range fitted {
+		if !math.IsNaN(prediction) {
+			residual := timeSeries.Values[i] - prediction
+			sum += residual * residual
+			count++
+		}
+	}
+	if count > 0 {
+		fs.sigma = math.Sqrt(sum / float64(count))
+	}
+	fs.isFitted = true
+}
+
+func (fs *forecastState) fittedValues() []float64 {
+	return fs.fitted
+}
+
+// checkHorizon returns an error when the forecast horizon is negative.
+func checkHorizon(horizon int) error {
+	if horizon < 0 {
+		return errors.New("horizon must not be negative")
+	}
+	return nil
+}
+
+// predict turns the point forecasts into a time series whose timestamps follow the fitted time series.
+func (fs *forecastState) predict(points []float64) (*TimeSeries, error) {
+	if !fs.isFitted {
+		return nil, errors.New("model must be fitted first")
+	}
+	timestamps := make([]float64, len(points))
+	for h := range points {
+		timestamps[h] = fs.lastTimestamp + float64(h+1)*fs.interval
+	}
+	return NewTimeSeries(timestamps, points), nil
+}
+
+// predictInterval adds a prediction interval around the point forecasts.
+// The variance factor returns the forecast variance at horizon h relative to the one-step ahead variance.
+func (fs *forecastState) predictInterval(points []float64, level float64, varianceFactor func(h int) float64) (*Forecast, error) {
+	if level <= 0 || level >= 1 {
+		return nil, errors.New("level must be within (0, 1)")
+	}
+	prediction, err := fs.predict(points)
+	if err != nil {
+		return nil, err
+	}
+
+	z := Quantile(0, 1)((1 + level) / 2)
+	forecast := &Forecast{
+		Timestamps: prediction.Timestamps,
+		Values:     prediction.Values,
+		Lower:      make([]float64, len(points)),
+		Upper:      make([]float64, len(points)),
+		Level:      level,
+	}
+	for h, point := range points {
+		margin := z * fs.sigma * math.Sqrt(varianceFactor(h+1))
+		forecast.Lower[h] = point - margin
+		forecast.Upper[h] = point + margin
+	}
+	return forecast, nil
+}
+
+// ForecastDetector holds the forecast based detector configuration.
+//
+// It fits the forecaster on the time series and scores each point by how far it falls
+// outside the one-step ahead prediction interval, in units of the residuals standard deviation.
+// Points within the interval score 0.
+type ForecastDetector struct {
+	forecaster Forecaster
+	level      float64
+}
+
+// NewForecastDetector returns ForecastDetector instance.
+func NewForecastDetector(forecaster Forecaster) *ForecastDetector {
+	return &ForecastDetector{forecaster: forecaster, level: 0.95}
+}
+
+// Level sets the prediction interval level (defaults to 0.95).
+func (fd *ForecastDetector) Level(level float64) *ForecastDetector {
+	fd.level = level
+	return fd
+}
+
+// Run runs the forecast detector over the time series.
+func (fd *ForecastDetector) Run(timeSeries *TimeSeries) *ScoreList {
+	scoreList, _ := fd.computeScores(timeSeries)
+	return scoreList
+}
+
+func (fd *ForecastDetector) computeScores(timeSeries *TimeSeries) (*ScoreList, error) {
+	if err := fd.forecaster.Fit(timeSeries); err != nil {
+		return nil, err
+	}
+
+	interval, err := fd.forecaster.PredictInterval(1, fd.level)
+	if err != nil {
+		return nil, err
+	}
+	halfWidth := (interval.Upper[0] - interval.Lower[0]) / 2
+	sigma := halfWidth / Quantile(0, 1)((1+fd.level)/2)
+
+	fitted := fd.forecaster.fittedValues()
+	scores := mapSliceWithIndex(timeSeries.Values, func(idx int, value float64) float64 {
+		if math.IsNaN(fitted[idx]) || sigma == 0 {
+			return 0.0
+		}
+		return math.Max(0, math.Abs(value-fitted[idx])-halfWidth) / sigma
+	})
+	return &ScoreList{timeSeries.Timestamps, scores}, nil
+}
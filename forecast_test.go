@@ -0,0 +1,95 @@
+package anomalia
+
+import (
+	"math"
+	"testing"
+)
+
+func TestForecasters(t *testing.T) {
+	ts := generatePeriodicTimeSeries(240, 12)
+	forecasters := map[string]Forecaster{
+		"ses":            NewSimpleExponentialSmoothing(),
+		"holt":           NewHolt(),
+		"holt-winters":   NewHoltWinters(12),
+		"seasonal-naive": NewSeasonalNaive(12),
+	}
+
+	for name, forecaster := range forecasters {
+		if _, err := forecaster.Predict(3); err == nil {
+			t.Fatalf("%s: must fail when the model is not fitted", name)
+		}
+
+		if err := forecaster.Fit(ts); err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+
+		forecast, err := forecaster.PredictInterval(24, 0.9)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if len(forecast.Values) != 24 || forecast.Timestamps[0] != 241 {
+			t.Fatalf("%s: forecast must follow the fitted time series", name)
+		}
+
+		for h := range forecast.Values {
+			if forecast.Lower[h] > forecast.Values[h] || forecast.Upper[h] < forecast.Values[h] {
+				t.Fatalf("%s: prediction interval must contain the forecast", name)
+			}
+		}
+	}
+}
+
+func TestForecastersRejectNegativeHorizon(t *testing.T) {
+	ts := generatePeriodicTimeSeries(240, 12)
+	forecasters := map[string]Forecaster{
+		"ses":            NewSimpleExponentialSmoothing(),
+		"holt":           NewHolt(),
+		"holt-winters":   NewHoltWinters(12),
+		"seasonal-naive": NewSeasonalNaive(12),
+		"arima":          NewARIMA(1, 0, 0),
+	}
+
+	for name, forecaster := range forecasters {
+		if err := forecaster.Fit(ts); err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if _, err := forecaster.Predict(-1); err == nil {
+			t.Fatalf("%s: must fail with a negative horizon", name)
+		}
+		if _, err := forecaster.PredictInterval(-1, 0.9); err == nil {
+			t.Fatalf("%s: must fail with a negative horizon", name)
+		}
+	}
+}
+
+func TestSeasonalForecastersFollowTheSeason(t *testing.T) {
+	ts := generatePeriodicTimeSeries(240, 12)
+	for _, forecaster := range []Forecaster{NewHoltWinters(12), NewSeasonalNaive(12)} {
+		_ = forecaster.Fit(ts)
+		prediction, _ := forecaster.Predict(12)
+		for h, value := range prediction.Values {
+			expected := math.Sin(2 * math.Pi * float64(240+h) / 12)
+			if math.Abs(value-expected) > 0.05 {
+				t.Fatalf("expected %v, got %v", expected, value)
+			}
+		}
+	}
+}
+
+func TestRunForecastDetector(t *testing.T) {
+	ts := generatePeriodicTimeSeries(240, 12)
+	ts.Values[200] += 3
+
+	scoreList := NewForecastDetector(NewHoltWinters(12)).Level(0.99).Run(ts)
+	if scoreList == nil {
+		t.Fatalf("score list cannot be nil")
+	}
+
+	if scoreList.Scores[200] != scoreList.Max() || scoreList.Scores[200] == 0 {
+		t.Fatalf("the spike must fall outside the prediction interval")
+	}
+
+	if scoreList.Min() < 0 {
+		t.Fatalf("scores must not be negative")
+	}
+}
@@ -0,0 +1,139 @@
+package anomalia
+
+import (
+	"errors"
+	"math"
+)
+
+// GrangerCausality holds the Granger causality test configuration.
+//
+// A time series Granger-causes another one when its past values help predicting the other one
+// beyond what the past values of the other one already do. The test compares an autoregressive
+// model of the effect with and without the lagged values of the cause using an F-test.
+type GrangerCausality struct {
+	current, target *TimeSeries
+	maxLag          int
+}
+
+// GrangerResult holds the Granger causality test result in one direction.
+type GrangerResult struct {
+	Lag                            int
+	FStatistic                     float64
+	PValue                         float64
+	NumeratorDF, DenominatorDF     int
+	RestrictedRSS, UnrestrictedRSS float64
+}
+
+// GrangerCausalityResult holds the Granger causality test results in both directions.
+type GrangerCausalityResult struct {
+	CurrentCausesTarget GrangerResult
+	TargetCausesCurrent GrangerResult
+}
+
+// NewGrangerCausality returns an instance of the Granger causality test.
+func NewGrangerCausality(current, target *TimeSeries) *GrangerCausality {
+	return &GrangerCausality{
+		current: current,
+		target:  target,
+		maxLag:  5,
+	}
+}
+
+// MaxLag sets the maximum lag (in points) considered. The lag is selected using the AIC (defaults to 5).
+func (gc *GrangerCausality) MaxLag(lag int) *GrangerCausality {
+	gc.maxLag = lag
+	return gc
+}
+
+// Test aligns both time series and runs the Granger causality test in both directions.
+func (gc *GrangerCausality) Test() (*GrangerCausalityResult, error) {
+	if gc.maxLag < 1 {
+		return nil, errors.New("max lag must be strictly positive")
+	}
+
+	// Align copies as alignment modifies the time series in place
+	current, target := copyTimeSeries(gc.current), copyTimeSeries(gc.target)
+	current.Align(target)
+	if current.Size()-gc.maxLag <= 2*gc.maxLag+1 {
+		return nil, errors.New("not enough data points for the max lag")
+	}
+
+	forward, err := grangerTest(current.Values, target.Values, gc.maxLag)
+	if err != nil {
+		return nil, err
+	}
+	backward, err := grangerTest(target.Values, current.Values, gc.maxLag)
+	if err != nil {
+		return nil, err
+	}
+	return &GrangerCausalityResult{
+		CurrentCausesTarget: forward,
+		TargetCausesCurrent: backward,
+	}, nil
+}
+
+// grangerTest tests whether cause Granger-causes effect.
+// The lag is selected by fitting the unrestricted model for every lag on the same sample and keeping the lowest AIC.
+func grangerTest(cause, effect []float64, maxLag int) (GrangerResult, error) {
+	bestLag, bestAIC := 1, math.Inf(1)
+	for lag := 1; lag <= maxLag; lag++ {
+		design, y := grangerDesign(cause, effect, lag, maxLag, true)
+		_, rss, err := leastSquares(design, y)
+		if err != nil {
+			return GrangerResult{}, err
+		}
+		n := float64(len(y))
+		if aic := n*math.Log(rss/n) + 2*float64(2*lag+1); aic < bestAIC {
+			bestLag, bestAIC = lag, aic
+		}
+	}
+
+	unrestrictedDesign, y := grangerDesign(cause, effect, bestLag, bestLag, true)
+	restrictedDesign, _ := grangerDesign(cause, effect, bestLag, bestLag, false)
+	_, unrestricted, err := leastSquares(unrestrictedDesign, y)
+	if err != nil {
+		return GrangerResult{}, err
+	}
+	_, restricted, err := leastSquares(restrictedDesign, y)
+	if err != nil {
+		return GrangerResult{}, err
+	}
+
+	numeratorDF, denominatorDF := bestLag, len(y)-2*bestLag-1
+	result := GrangerResult{
+		Lag:             bestLag,
+		NumeratorDF:     numeratorDF,
+		DenominatorDF:   denominatorDF,
+		RestrictedRSS:   restricted,
+		UnrestrictedRSS: unrestricted,
+		PValue:          1.0,
+	}
+	if unrestricted > 0 {
+		result.FStatistic = math.Max((restricted-unrestricted)/float64(numeratorDF)/(unrestricted/float64(denominatorDF)), 0)
+		result.PValue = 1 - FCdf(float64(numeratorDF), float64(denominatorDF))(result.FStatistic)
+	} else if restricted > 0 {
+		result.FStatistic, result.PValue = math.Inf(1), 0.0
+	}
+	return result, nil
+}
+
+// grangerDesign builds the regression of effect on a constant, its own lags and (when unrestricted) the lags of cause.
+// The sample starts at start so that models with different lags can be compared on the same observations.
+func grangerDesign(cause, effect []float64, lag, start int, unrestricted bool) ([][]float64, []float64) {
+	design := make([][]float64, 0, len(effect)-start)
+	y := make([]float64, 0, len(effect)-start)
+	for t := start; t < len(effect); t++ {
+		row := []float64{1}
+		for l := 1; l <= lag; l++ {
+			row = append(row, effect[t-l])
+		}
+		if unrestricted {
+			for l := 1; l <= lag; l++ {
+				row = append(row, cause[t-l])
+			}
+		}
+		design = append(design, row)
+		y = append(y, effect[t])
+	}
+	return design, y
+}
@@ -0,0 +1,41 @@
+package anomalia
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestGrangerCausality(t *testing.T) {
+	rng := rand.New(rand.NewSource(8))
+	size := 300
+	timestamps, cause, effect := make([]float64, size), make([]float64, size), make([]float64, size)
+	for i := 0; i < size; i++ {
+		timestamps[i] = float64(i)
+		cause[i] = rng.NormFloat64()
+		if i >= 2 {
+			cause[i] += 0.5 * cause[i-1]
+			effect[i] = 0.3*effect[i-1] + 0.8*cause[i-2] + 0.5*rng.NormFloat64()
+		}
+	}
+
+	result, err := NewGrangerCausality(NewTimeSeries(timestamps, cause), NewTimeSeries(copySlice(timestamps), effect)).
+		MaxLag(4).
+		Test()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.CurrentCausesTarget.PValue > 1e-6 || result.CurrentCausesTarget.Lag < 2 {
+		t.Fatalf("expected current to cause target at lag >= 2, got %+v", result.CurrentCausesTarget)
+	}
+	if result.TargetCausesCurrent.PValue < 0.01 {
+		t.Fatalf("expected target not to cause current, got %+v", result.TargetCausesCurrent)
+	}
+}
+
+func TestGrangerCausalityWithTooFewPoints(t *testing.T) {
+	timeSeries := NewTimeSeries([]float64{0, 1, 2, 3, 4, 5}, []float64{1, 2, 3, 2, 1, 2})
+	if _, err := NewGrangerCausality(timeSeries, copyTimeSeries(timeSeries)).MaxLag(3).Test(); err == nil {
+		t.Fatalf("must fail when there are not enough data points")
+	}
+}
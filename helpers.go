@@ -10,6 +10,9 @@ type mapperWithIndex func(int, float64) float64
 type predicate func(float64) bool
 
 func minMax(data []float64) (float64, float64) {
+	if len(data) == 0 {
+		return 0.0, 0.0
+	}
 	var (
 		max = data[0]
 		min = data[0]
@@ -150,3 +153,17 @@ func sumOfProducts(s1 []float64, s2 []float64) float64 {
 	}
 	return sum
 }
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
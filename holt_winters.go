@@ -0,0 +1,189 @@
+package anomalia
+
+import (
+	"errors"
+	"math"
+)
+
+// Holt holds Holt's linear trend method configuration.
+// It extends exponential smoothing with a smoothed trend component.
+type Holt struct {
+	alpha, beta  float64
+	level, trend float64
+	forecastState
+}
+
+// NewHolt returns Holt instance.
+func NewHolt() *Holt {
+	return &Holt{alpha: 0.5, beta: 0.1}
+}
+
+// Alpha sets the level smoothing factor (defaults to 0.5).
+func (h *Holt) Alpha(alpha float64) *Holt {
+	h.alpha = alpha
+	return h
+}
+
+// Beta sets the trend smoothing factor (defaults to 0.1).
+func (h *Holt) Beta(beta float64) *Holt {
+	h.beta = beta
+	return h
+}
+
+// Fit fits the model on the time series.
+func (h *Holt) Fit(timeSeries *TimeSeries) error {
+	values := timeSeries.Values
+	if len(values) < 2 {
+		return errors.New("not enough data points")
+	}
+
+	fitted := make([]float64, len(values))
+	fitted[0] = math.NaN()
+	h.level, h.trend = values[0], values[1]-values[0]
+	for i := 1; i < len(values); i++ {
+		fitted[i] = h.level + h.trend
+		level := h.alpha*values[i] + (1-h.alpha)*(h.level+h.trend)
+		h.trend = h.beta*(level-h.level) + (1-h.beta)*h.trend
+		h.level = level
+	}
+	h.fit(timeSeries, fitted)
+	return nil
+}
+
+// Predict returns the forecast of the next horizon points.
+func (h *Holt) Predict(horizon int) (*TimeSeries, error) {
+	if err := checkHorizon(horizon); err != nil {
+		return nil, err
+	}
+	return h.predict(h.points(horizon))
+}
+
+// PredictInterval returns the forecast of the next horizon points with their prediction interval.
+func (h *Holt) PredictInterval(horizon int, level float64) (*Forecast, error) {
+	if err := checkHorizon(horizon); err != nil {
+		return nil, err
+	}
+	return h.predictInterval(h.points(horizon), level, func(horizon int) float64 {
+		factor := 1.0
+		for j := 1; j < horizon; j++ {
+			c := h.alpha * (1 + float64(j)*h.beta)
+			factor += c * c
+		}
+		return factor
+	})
+}
+
+func (h *Holt) points(horizon int) []float64 {
+	points := make([]float64, horizon)
+	for i := range points {
+		points[i] = h.level + float64(i+1)*h.trend
+	}
+	return points
+}
+
+// HoltWinters holds the additive Holt-Winters method configuration.
+// It extends Holt's linear trend method with a smoothed seasonal component.
+type HoltWinters struct {
+	alpha, beta, gamma float64
+	period             int
+	level, trend       float64
+	seasonal           []float64
+	offset             int
+	forecastState
+}
+
+// NewHoltWinters returns HoltWinters instance for the specified seasonal period.
+func NewHoltWinters(period int) *HoltWinters {
+	return &HoltWinters{alpha: 0.5, beta: 0.1, gamma: 0.1, period: period}
+}
+
+// Alpha sets the level smoothing factor (defaults to 0.5).
+func (hw *HoltWinters) Alpha(alpha float64) *HoltWinters {
+	hw.alpha = alpha
+	return hw
+}
+
+// Beta sets the trend smoothing factor (defaults to 0.1).
+func (hw *HoltWinters) Beta(beta float64) *HoltWinters {
+	hw.beta = beta
+	return hw
+}
+
+// Gamma sets the seasonal smoothing factor (defaults to 0.1).
+func (hw *HoltWinters) Gamma(gamma float64) *HoltWinters {
+	hw.gamma = gamma
+	return hw
+}
+
+// Fit fits the model on the time series.
+// The first two seasons are used to initialize the level, trend and seasonal components.
+func (hw *HoltWinters) Fit(timeSeries *TimeSeries) error {
+	values, m := timeSeries.Values, hw.period
+	if m < 2 {
+		return errors.New("period must be at least 2")
+	}
+	if len(values) < 2*m {
+		return errors.New("not enough data points")
+	}
+
+	firstSeason, secondSeason := Average(values[:m]), Average(values[m:2*m])
+	hw.level = firstSeason
+	hw.trend = (secondSeason - firstSeason) / float64(m)
+	hw.seasonal = make([]float64, m)
+	for i := 0; i < m; i++ {
+		hw.seasonal[i] = values[i] - firstSeason
+	}
+
+	fitted := make([]float64, len(values))
+	for i := 0; i < m; i++ {
+		fitted[i] = math.NaN()
+	}
+	for i := m; i < len(values); i++ {
+		season := hw.seasonal[i%m]
+		fitted[i] = hw.level + hw.trend + season
+		level := hw.alpha*(values[i]-season) + (1-hw.alpha)*(hw.level+hw.trend)
+		hw.trend = hw.beta*(level-hw.level) + (1-hw.beta)*hw.trend
+		hw.seasonal[i%m] = hw.gamma*(values[i]-level) + (1-hw.gamma)*season
+		hw.level = level
+	}
+	hw.offset = len(values)
+	hw.fit(timeSeries, fitted)
+	return nil
+}
+
+// Predict returns the forecast of the next horizon points.
+func (hw *HoltWinters) Predict(horizon int) (*TimeSeries, error) {
+	if err := checkHorizon(horizon); err != nil {
+		return nil, err
+	}
+	return hw.predict(hw.points(horizon))
+}
+
+// PredictInterval returns the forecast of the next horizon points with their prediction interval.
+func (hw *HoltWinters) PredictInterval(horizon int, level float64) (*Forecast, error) {
+	if err := checkHorizon(horizon); err != nil {
+		return nil, err
+	}
+	return hw.predictInterval(hw.points(horizon), level, func(horizon int) float64 {
+		factor := 1.0
+		for j := 1; j < horizon; j++ {
+			c := hw.alpha * (1 + float64(j)*hw.beta)
+			if j%hw.period == 0 {
+				c += hw.gamma * (1 - hw.alpha)
+			}
+			factor += c * c
+		}
+		return factor
+	})
+}
+
+func (hw *HoltWinters) points(horizon int) []float64 {
+	points := make([]float64, horizon)
+	if !hw.isFitted {
+		return points
+	}
+	for i := range points {
+		points[i] = hw.level + float64(i+1)*hw.trend + hw.seasonal[(hw.offset+i)%hw.period]
+	}
+	return points
+}
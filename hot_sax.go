@@ -0,0 +1,179 @@
+package anomalia
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// HotSax holds the HOT SAX discord discovery algorithm configuration.
+//
+// The discords are the subsequences that have the largest distance to their nearest non-overlapping neighbour.
+// HOT SAX uses the SAX words of the subsequences to visit rare words first in the outer loop and similar
+// words first in the inner loop, which allows abandoning most of the distance computations early.
+// The paper describing this algorithm can be found here: https://doi.org/10.1109/ICDM.2005.79
+type HotSax struct {
+	windowSize int
+	discords   int
+	*SAX
+}
+
+// NewHotSax returns HotSax instance.
+func NewHotSax() *HotSax {
+	return &HotSax{
+		windowSize: 32,
+		discords:   1,
+		SAX:        NewSAX().Segments(4).AlphabetSize(3),
+	}
+}
+
+// WindowSize sets the subsequence length (defaults to 32).
+func (hs *HotSax) WindowSize(size int) *HotSax {
+	hs.windowSize = size
+	return hs
+}
+
+// Discords sets the number of discords to discover (defaults to 1).
+func (hs *HotSax) Discords(k int) *HotSax {
+	hs.discords = k
+	return hs
+}
+
+// Run runs the HOT SAX algorithm over the time series.
+// Discords are scored using their nearest neighbour distance and all other points score 0.
+func (hs *HotSax) Run(timeSeries *TimeSeries) *ScoreList {
+	scoreList, _ := hs.computeScores(timeSeries)
+	return scoreList
+}
+
+// FindDiscords returns the discovered discords ordered by decreasing distance.
+func (hs *HotSax) FindDiscords(timeSeries *TimeSeries) ([]Subsequence, error) {
+	if err := hs.sanityCheck(timeSeries); err != nil {
+		return nil, err
+	}
+
+	var (
+		m        = hs.windowSize
+		size     = timeSeries.Size() - m + 1
+		rnd      = rand.New(rand.NewSource(1))
+		words    = make([]BitmapBinary, size)
+		buckets  = make(map[BitmapBinary][]int)
+		normed   = make([][]float64, size)
+		discords = make([]Subsequence, 0, hs.discords)
+	)
+
+	for i := 0; i < size; i++ {
+		normed[i] = ZNormalize(timeSeries.Values[i : i+m])
+		words[i] = discretize(PAA(normed[i], hs.segments), hs.alphabetSize)
+		buckets[words[i]] = append(buckets[words[i]], i)
+	}
+
+	// Outer loop visits subsequences with the rarest words first
+	outer := make([]int, size)
+	for i := range outer {
+		outer[i] = i
+	}
+	sort.SliceStable(outer, func(i, j int) bool { return len(buckets[words[outer[i]]]) < len(buckets[words[outer[j]]]) })
+
+	isExcluded := func(idx int) bool {
+		for _, discord := range discords {
+			if AbsInt(idx-discord.Index) < m {
+				return true
+			}
+		}
+		return false
+	}
+
+	for len(discords) < hs.discords {
+		best := Subsequence{Index: -1, Distance: -1}
+		for _, i := range outer {
+			if isExcluded(i) {
+				continue
+			}
+
+			nearest, neighbor := math.Inf(1), -1
+			visit := func(j int) bool {
+				if AbsInt(i-j) < m {
+					return true
+				}
+				if distance := earlyAbandonDistance(normed[i], normed[j], nearest); distance < nearest {
+					nearest, neighbor = distance, j
+				}
+				// Abandon the candidate: it cannot be the best discord
+				return nearest >= best.Distance
+			}
+
+			// Inner loop visits subsequences with the same word first, then the others in random order
+			abandoned := false
+			for _, j := range buckets[words[i]] {
+				if !visit(j) {
+					abandoned = true
+					break
+				}
+			}
+			if !abandoned {
+				for _, j := range rnd.Perm(size) {
+					if words[j] != words[i] && !visit(j) {
+						abandoned = true
+						break
+					}
+				}
+			}
+
+			if !abandoned && neighbor != -1 && nearest > best.Distance {
+				best = Subsequence{
+					Index:             i,
+					Timestamp:         timeSeries.Timestamps[i],
+					NeighborIndex:     neighbor,
+					NeighborTimestamp: timeSeries.Timestamps[neighbor],
+					Distance:          nearest,
+				}
+			}
+		}
+
+		if best.Index == -1 {
+			break
+		}
+		discords = append(discords, best)
+	}
+	return discords, nil
+}
+
+func (hs *HotSax) computeScores(timeSeries *TimeSeries) (*ScoreList, error) {
+	discords, err := hs.FindDiscords(timeSeries)
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make([]float64, timeSeries.Size())
+	for _, discord := range discords {
+		scores[discord.Index] = discord.Distance
+	}
+	return &ScoreList{timeSeries.Timestamps, scores}, nil
+}
+
+func (hs *HotSax) sanityCheck(timeSeries *TimeSeries) error {
+	if hs.windowSize < 2 || timeSeries.Size() < 2*hs.windowSize {
+		return errors.New("not enough data points")
+	}
+	if hs.discords < 1 {
+		return errors.New("number of discords must be positive")
+	}
+	return hs.SAX.sanityCheck(make([]float64, hs.windowSize))
+}
+
+// earlyAbandonDistance returns the euclidean distance between both sequences
+// or +Inf as soon as the distance exceeds the cutoff.
+func earlyAbandonDistance(a, b []float64, cutoff float64) float64 {
+	limit := cutoff * cutoff
+	sum := 0.0
+	for i := range a {
+		d := a[i] - b[i]
+		sum += d * d
+		if sum >= limit {
+			return math.Inf(1)
+		}
+	}
+	return math.Sqrt(sum)
+}
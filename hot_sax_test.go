@@ -0,0 +1,40 @@
+package anomalia
+
+import "testing"
+
+func TestRunHotSax(t *testing.T) {
+	timeSeries := generatePeriodicTimeSeries(400, 20)
+	for i := 300; i < 305; i++ {
+		timeSeries.Values[i] = 0
+	}
+
+	scoreList := NewHotSax().WindowSize(20).Run(timeSeries)
+	if scoreList == nil {
+		t.Fatalf("score list cannot be nil")
+	}
+
+	if len(scoreList.Scores) != timeSeries.Size() {
+		t.Fatalf("score list must have the same dimension as original time series")
+	}
+
+	if idx := indexOf(scoreList.Scores, scoreList.Max()); idx < 300-20 || idx > 305 {
+		t.Fatalf("discord must overlap the distorted cycle, got index %d", idx)
+	}
+}
+
+func TestHotSaxMatchesMatrixProfile(t *testing.T) {
+	timeSeries := generateFakeTimeSeries(300)
+	discords, err := NewHotSax().WindowSize(16).Discords(2).FindDiscords(timeSeries)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	profile, _ := NewMatrixProfile().WindowSize(16).ExclusionZone(15).Profile(timeSeries)
+	expected := profile.Discords(1)[0]
+	if discords[0].Index != expected.Index {
+		t.Fatalf("expected discord at %d, got %d", expected.Index, discords[0].Index)
+	}
+	if len(discords) != 2 || discords[1].Distance > discords[0].Distance {
+		t.Fatalf("discords must be ordered by decreasing distance")
+	}
+}
@@ -0,0 +1,212 @@
+package anomalia
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+)
+
+// IsolationForest holds the isolation forest algorithm configuration.
+//
+// Each data point is embedded as a feature vector made of its value, lagged values,
+// rolling mean and standard deviation and its derivative. An ensemble of random trees then
+// isolates the points: anomalies are easier to isolate and so have shorter paths.
+// The paper describing this algorithm can be found here: https://doi.org/10.1109/ICDM.2008.17
+type IsolationForest struct {
+	trees      int
+	sampleSize int
+	seed       int64
+	lags       int
+	windowSize int
+}
+
+type isolationNode struct {
+	feature     int
+	split       float64
+	size        int
+	left, right *isolationNode
+}
+
+// NewIsolationForest returns IsolationForest instance.
+func NewIsolationForest() *IsolationForest {
+	return &IsolationForest{
+		trees:      100,
+		sampleSize: 256,
+		seed:       1,
+		lags:       3,
+		windowSize: 10,
+	}
+}
+
+// Trees sets the number of trees in the forest (defaults to 100).
+func (f *IsolationForest) Trees(n int) *IsolationForest {
+	f.trees = n
+	return f
+}
+
+// SampleSize sets the number of points used to build each tree (defaults to 256).
+func (f *IsolationForest) SampleSize(size int) *IsolationForest {
+	f.sampleSize = size
+	return f
+}
+
+// Seed sets the seed of the random source which makes results deterministic (defaults to 1).
+func (f *IsolationForest) Seed(seed int64) *IsolationForest {
+	f.seed = seed
+	return f
+}
+
+// Lags sets the number of lagged values in each feature vector (defaults to 3).
+func (f *IsolationForest) Lags(n int) *IsolationForest {
+	f.lags = n
+	return f
+}
+
+// WindowSize sets the size of the rolling window used for mean and standard deviation (defaults to 10).
+func (f *IsolationForest) WindowSize(size int) *IsolationForest {
+	f.windowSize = size
+	return f
+}
+
+// Run runs the isolation forest algorithm over the time series.
+func (f *IsolationForest) Run(timeSeries *TimeSeries) *ScoreList {
+	scoreList, _ := f.computeScores(timeSeries)
+	return scoreList
+}
+
+func (f *IsolationForest) computeScores(timeSeries *TimeSeries) (*ScoreList, error) {
+	if err := f.sanityCheck(timeSeries); err != nil {
+		return nil, err
+	}
+
+	features := f.embed(timeSeries)
+	sampleSize := f.sampleSize
+	if sampleSize > len(features) {
+		sampleSize = len(features)
+	}
+	heightLimit := int(math.Ceil(math.Log2(float64(sampleSize))))
+
+	rnd := rand.New(rand.NewSource(f.seed))
+	forest := make([]*isolationNode, f.trees)
+	for i := range forest {
+		sample := make([][]float64, sampleSize)
+		for j, idx := range rnd.Perm(len(features))[:sampleSize] {
+			sample[j] = features[idx]
+		}
+		forest[i] = buildIsolationTree(rnd, sample, 0, heightLimit)
+	}
+
+	normalization := averagePathLength(sampleSize)
+	scores := mapSliceWithIndex(timeSeries.Values, func(idx int, _ float64) float64 {
+		total := 0.0
+		for _, tree := range forest {
+			total += tree.pathLength(features[idx], 0)
+		}
+		return math.Pow(2, -(total/float64(len(forest)))/normalization)
+	})
+	return &ScoreList{timeSeries.Timestamps, scores}, nil
+}
+
+// embed builds the feature vector of each data point.
+func (f *IsolationForest) embed(timeSeries *TimeSeries) [][]float64 {
+	values := timeSeries.Values
+	derivatives := NewDerivative().computeDerivatives(timeSeries)
+	features := make([][]float64, len(values))
+
+	for idx, value := range values {
+		vector := make([]float64, 0, f.lags+4)
+		vector = append(vector, value)
+		for lag := 1; lag <= f.lags; lag++ {
+			vector = append(vector, values[maxInt(idx-lag, 0)])
+		}
+		window := values[maxInt(idx-f.windowSize+1, 0) : idx+1]
+		vector = append(vector, Average(window), Stdev(window), derivatives[idx])
+		features[idx] = vector
+	}
+	return features
+}
+
+func (f *IsolationForest) sanityCheck(timeSeries *TimeSeries) error {
+	if timeSeries.Size() < 2 {
+		return errors.New("not enough data points")
+	}
+	if f.trees < 1 || f.sampleSize < 2 {
+		return errors.New("invalid forest configuration")
+	}
+	if f.windowSize < 1 {
+		return errors.New("window size must be at least 1")
+	}
+	if f.lags < 0 {
+		return errors.New("number of lags must not be negative")
+	}
+	return nil
+}
+
+func buildIsolationTree(rnd *rand.Rand, sample [][]float64, height, heightLimit int) *isolationNode {
+	if height >= heightLimit || len(sample) <= 1 {
+		return &isolationNode{size: len(sample)}
+	}
+
+	// Only split on features that are not constant within the sample
+	dimension := len(sample[0])
+	candidates := make([]int, 0, dimension)
+	for feature := 0; feature < dimension; feature++ {
+		if min, max := featureRange(sample, feature); min < max {
+			candidates = append(candidates, feature)
+		}
+	}
+	if len(candidates) == 0 {
+		return &isolationNode{size: len(sample)}
+	}
+
+	feature := candidates[rnd.Intn(len(candidates))]
+	min, max := featureRange(sample, feature)
+	split := min + rnd.Float64()*(max-min)
+
+	var left, right [][]float64
+	for _, vector := range sample {
+		if vector[feature] < split {
+			left = append(left, vector)
+		} else {
+			right = append(right, vector)
+		}
+	}
+	return &isolationNode{
+		feature: feature,
+		split:   split,
+		size:    len(sample),
+		left:    buildIsolationTree(rnd, left, height+1, heightLimit),
+		right:   buildIsolationTree(rnd, right, height+1, heightLimit),
+	}
+}
+
+func (node *isolationNode) pathLength(vector []float64, height int) float64 {
+	if node.left == nil && node.right == nil {
+		return float64(height) + averagePathLength(node.size)
+	}
+	if vector[node.feature] < node.split {
+		return node.left.pathLength(vector, height+1)
+	}
+	return node.right.pathLength(vector, height+1)
+}
+
+func featureRange(sample [][]float64, feature int) (float64, float64) {
+	min, max := sample[0][feature], sample[0][feature]
+	for _, vector := range sample {
+		min = math.Min(min, vector[feature])
+		max = math.Max(max, vector[feature])
+	}
+	return min, max
+}
+
+// averagePathLength returns the average path length of an unsuccessful search in a binary search tree of n nodes.
+func averagePathLength(n int) float64 {
+	if n <= 1 {
+		return 0.0
+	}
+	if n == 2 {
+		return 1.0
+	}
+	harmonic := math.Log(float64(n-1)) + 0.5772156649
+	return 2*harmonic - 2*float64(n-1)/float64(n)
+}
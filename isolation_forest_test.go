@@ -0,0 +1,58 @@
+package anomalia
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestRunIsolationForest(t *testing.T) {
+	timeSeries := generatePeriodicTimeSeries(500, 50)
+	random := rand.New(rand.NewSource(3))
+	for i := range timeSeries.Values {
+		timeSeries.Values[i] += 0.1 * random.NormFloat64()
+	}
+	timeSeries.Values[250] = 100
+
+	// Without lagged features, the outlier is not carried into the following points
+	scoreList := NewIsolationForest().Trees(50).SampleSize(128).Lags(0).Seed(42).Run(timeSeries)
+	if scoreList == nil {
+		t.Fatalf("score list cannot be nil")
+	}
+
+	if len(scoreList.Scores) != timeSeries.Size() {
+		t.Fatalf("score list must have the same dimension as original time series")
+	}
+
+	if top := scoreList.TopK(1).Timestamps[0]; top != timeSeries.Timestamps[250] {
+		t.Fatalf("the outlier must have the highest score, got timestamp %v", top)
+	}
+}
+
+func TestIsolationForestIsDeterministic(t *testing.T) {
+	timeSeries := generateFakeTimeSeries(300)
+	first := NewIsolationForest().Seed(7).Run(timeSeries)
+	second := NewIsolationForest().Seed(7).Run(timeSeries)
+
+	for i := range first.Scores {
+		if first.Scores[i] != second.Scores[i] {
+			t.Fatalf("scores must be the same when using the same seed")
+		}
+	}
+}
+
+func TestRunIsolationForestWithInvalidFeatures(t *testing.T) {
+	timeSeries := generatePeriodicTimeSeries(100, 10)
+	if scoreList := NewIsolationForest().WindowSize(0).Run(timeSeries); scoreList != nil {
+		t.Fatalf("score list must be nil (invalid window size)")
+	}
+	if scoreList := NewIsolationForest().Lags(-1).Run(timeSeries); scoreList != nil {
+		t.Fatalf("score list must be nil (invalid number of lags)")
+	}
+}
+
+func TestRunIsolationForestWhenNotEnoughDataPoints(t *testing.T) {
+	timeSeries := NewTimeSeries([]float64{1}, []float64{1})
+	if scoreList := NewIsolationForest().Run(timeSeries); scoreList != nil {
+		t.Fatalf("score list must be nil (not enough data points)")
+	}
+}
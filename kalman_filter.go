@@ -0,0 +1,223 @@
+package anomalia
+
+import (
+	"errors"
+	"math"
+)
+
+// KalmanModel type checker for the state space model used by the Kalman filter
+type KalmanModel int32
+
+const (
+	// LocalLevel models the time series as a random walk level observed with noise.
+	LocalLevel KalmanModel = iota
+
+	// LocalLinearTrend models the time series as a level with a random walk slope observed with noise.
+	LocalLinearTrend
+)
+
+// KalmanFilter holds the Kalman filter algorithm configuration and state.
+//
+// The filter tracks the level (and slope) of the time series and adapts smoothly to level changes.
+// Each point is scored by its standardized innovation: the difference between the observed and
+// predicted values divided by the predicted standard deviation.
+// Irregular timestamps are handled natively by scaling the state noise with the elapsed time
+// relative to the median sampling interval.
+//
+// Unless configured, the noise variances are estimated by maximum likelihood when running over a time series.
+// After a run, the filter can keep scoring new points in streaming mode using Update.
+type KalmanFilter struct {
+	model               KalmanModel
+	observationVariance float64
+	levelVariance       float64
+	slopeVariance       float64
+	estimate            bool
+	interval            float64
+	state               [2]float64
+	covariance          [2][2]float64
+	lastTimestamp       float64
+	observations        int
+}
+
+// NewKalmanFilter returns KalmanFilter instance.
+func NewKalmanFilter() *KalmanFilter {
+	return &KalmanFilter{model: LocalLevel, estimate: true, interval: 1}
+}
+
+// Model sets the state space model (defaults to LocalLevel).
+func (kf *KalmanFilter) Model(model KalmanModel) *KalmanFilter {
+	kf.model = model
+	return kf
+}
+
+// Variances sets the observation, level and slope noise variances which disables their estimation.
+// The slope variance is ignored by the LocalLevel model.
+func (kf *KalmanFilter) Variances(observation, level, slope float64) *KalmanFilter {
+	kf.observationVariance, kf.levelVariance, kf.slopeVariance = observation, level, slope
+	kf.estimate = false
+	return kf
+}
+
+// Run runs the Kalman filter over the time series.
+// The filter state is reset before the run and kept afterwards for streaming.
+func (kf *KalmanFilter) Run(timeSeries *TimeSeries) *ScoreList {
+	scoreList, _ := kf.computeScores(timeSeries)
+	return scoreList
+}
+
+// Update filters a new observation in streaming mode and returns its score.
+// The first observations initialize the state and score 0.
+// When neither Run nor Variances were called first, the filter starts with the same variances
+// as the maximum likelihood search on a unit scale, i.e. 0.5 for the observation and level noises.
+func (kf *KalmanFilter) Update(timestamp, value float64) float64 {
+	if kf.estimate && kf.observationVariance == 0 && kf.levelVariance == 0 {
+		kf.setVariances(initialVariances(kf.model, 1))
+	}
+	innovation, variance := kf.step(timestamp, value)
+	if math.IsNaN(innovation) || variance <= 0 {
+		return 0.0
+	}
+	return math.Abs(innovation) / math.Sqrt(variance)
+}
+
+// Level returns the current estimate of the level.
+func (kf *KalmanFilter) Level() float64 {
+	return kf.state[0]
+}
+
+func (kf *KalmanFilter) computeScores(timeSeries *TimeSeries) (*ScoreList, error) {
+	if timeSeries.Size() < kf.dimension()+2 {
+		return nil, errors.New("not enough data points")
+	}
+
+	kf.interval = timeSeries.samplingInterval()
+	if kf.interval <= 0 {
+		kf.interval = 1
+	}
+	if kf.estimate {
+		kf.estimateVariances(timeSeries)
+	}
+
+	kf.reset()
+	scores := make([]float64, timeSeries.Size())
+	for i, timestamp := range timeSeries.Timestamps {
+		scores[i] = kf.Update(timestamp, timeSeries.Values[i])
+	}
+	return &ScoreList{timeSeries.Timestamps, scores}, nil
+}
+
+// estimateVariances finds the noise variances maximizing the likelihood of the time series.
+// Variances are optimized on the log scale to keep them positive.
+func (kf *KalmanFilter) estimateVariances(timeSeries *TimeSeries) {
+	scale := Variance(difference(timeSeries.Values, 1))
+	if scale == 0 {
+		scale = 1
+	}
+
+	initial := initialVariances(kf.model, scale)
+	likelihood := func(params []float64) float64 {
+		kf.setVariances(params)
+		kf.reset()
+
+		deviance := 0.0
+		for i, timestamp := range timeSeries.Timestamps {
+			innovation, variance := kf.step(timestamp, timeSeries.Values[i])
+			if math.IsNaN(innovation) {
+				continue
+			}
+			if variance <= 0 {
+				return math.Inf(1)
+			}
+			deviance += math.Log(variance) + innovation*innovation/variance
+		}
+		return deviance
+	}
+	kf.setVariances(minimize(likelihood, initial))
+}
+
+// initialVariances returns the log variances from which the maximum likelihood search starts.
+func initialVariances(model KalmanModel, scale float64) []float64 {
+	initial := []float64{math.Log(scale / 2), math.Log(scale / 2)}
+	if model == LocalLinearTrend {
+		initial = append(initial, math.Log(scale/100))
+	}
+	return initial
+}
+
+func (kf *KalmanFilter) setVariances(params []float64) {
+	kf.observationVariance = math.Exp(params[0])
+	kf.levelVariance = math.Exp(params[1])
+	if len(params) > 2 {
+		kf.slopeVariance = math.Exp(params[2])
+	}
+}
+
+func (kf *KalmanFilter) reset() {
+	kf.state = [2]float64{}
+	kf.covariance = [2][2]float64{}
+	kf.observations = 0
+}
+
+func (kf *KalmanFilter) dimension() int {
+	if kf.model == LocalLinearTrend {
+		return 2
+	}
+	return 1
+}
+
+// step filters the observation and returns the innovation with its variance.
+// The innovation is NaN while the state is initialized from the first observations.
+func (kf *KalmanFilter) step(timestamp, value float64) (float64, float64) {
+	defer func() {
+		kf.lastTimestamp = timestamp
+		kf.observations++
+	}()
+
+	switch {
+	case kf.observations == 0:
+		kf.state = [2]float64{value, 0}
+		kf.covariance = [2][2]float64{{kf.observationVariance, 0}, {0, 0}}
+		return math.NaN(), 0
+	case kf.observations == 1 && kf.model == LocalLinearTrend:
+		dt := kf.elapsed(timestamp)
+		kf.state = [2]float64{value, (value - kf.state[0]) / dt}
+		kf.covariance = [2][2]float64{
+			{kf.observationVariance, kf.observationVariance / dt},
+			{kf.observationVariance / dt, 2 * kf.observationVariance / (dt * dt)},
+		}
+		return math.NaN(), 0
+	}
+
+	// Predict the state at the observation timestamp
+	dt := kf.elapsed(timestamp)
+	p := kf.covariance
+	if kf.model == LocalLinearTrend {
+		kf.state[0] += dt * kf.state[1]
+		p = [2][2]float64{
+			{p[0][0] + dt*(p[1][0]+p[0][1]) + dt*dt*p[1][1] + dt*kf.levelVariance, p[0][1] + dt*p[1][1]},
+			{p[1][0] + dt*p[1][1], p[1][1] + dt*kf.slopeVariance},
+		}
+	} else {
+		p[0][0] += dt * kf.levelVariance
+	}
+
+	// Update the state using the observation
+	innovation := value - kf.state[0]
+	variance := p[0][0] + kf.observationVariance
+	gain := [2]float64{p[0][0] / variance, p[1][0] / variance}
+	kf.state[0] += gain[0] * innovation
+	kf.state[1] += gain[1] * innovation
+	kf.covariance = [2][2]float64{
+		{p[0][0] - gain[0]*p[0][0], p[0][1] - gain[0]*p[0][1]},
+		{p[1][0] - gain[1]*p[0][0], p[1][1] - gain[1]*p[0][1]},
+	}
+	return innovation, variance
+}
+
+// elapsed returns the time elapsed since the last observation in sampling intervals.
+func (kf *KalmanFilter) elapsed(timestamp float64) float64 {
+	if dt := (timestamp - kf.lastTimestamp) / kf.interval; dt > 0 {
+		return dt
+	}
+	return 1
+}
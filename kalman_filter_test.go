@@ -0,0 +1,70 @@
+package anomalia
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRunWithKalmanFilter(t *testing.T) {
+	ts := generateAutoregressiveTimeSeries(300, 0.9)
+	ts.Values[200] += 8
+
+	for _, model := range []KalmanModel{LocalLevel, LocalLinearTrend} {
+		scoreList := NewKalmanFilter().Model(model).Run(ts)
+		if scoreList == nil {
+			t.Fatalf("score list cannot be nil")
+		}
+
+		if scoreList.Scores[200] != scoreList.Max() {
+			t.Fatalf("the spike must have the highest score (model %d)", model)
+		}
+	}
+}
+
+func TestKalmanFilterAdaptsToLevelShift(t *testing.T) {
+	timestamps := make([]float64, 100)
+	values := make([]float64, 100)
+	for i := range values {
+		timestamps[i] = float64(i)
+		if i >= 50 {
+			values[i] = 10
+		}
+	}
+
+	kf := NewKalmanFilter().Variances(1, 0.5, 0)
+	scoreList := kf.Run(NewTimeSeries(timestamps, values))
+	if scoreList.Scores[50] != scoreList.Max() {
+		t.Fatalf("the level shift must have the highest score")
+	}
+
+	if math.Abs(kf.Level()-10) > 1e-3 {
+		t.Fatalf("level must adapt to the shift, got %v", kf.Level())
+	}
+
+	// Streaming keeps using the filter state, and a long gap widens the predicted variance
+	if score := kf.Update(100, 10); score > 1e-3 {
+		t.Fatalf("expected point must have a zero score, got %v", score)
+	}
+	afterStep := kf.Update(101, 15)
+	afterGap := kf.Update(1000, 20)
+	if afterStep <= afterGap {
+		t.Fatalf("deviation after a long gap must be less surprising")
+	}
+}
+
+func TestKalmanFilterUpdateWithoutRun(t *testing.T) {
+	kf := NewKalmanFilter()
+	for i := 0; i < 10; i++ {
+		kf.Update(float64(i), 10)
+	}
+	if score := kf.Update(10, 30); score <= 0 {
+		t.Fatalf("a deviation must score above 0 without a prior run, got %v", score)
+	}
+}
+
+func TestRunKalmanFilterWhenNotEnoughDataPoints(t *testing.T) {
+	ts := NewTimeSeries([]float64{1, 2}, []float64{1, 2})
+	if scoreList := NewKalmanFilter().Run(ts); scoreList != nil {
+		t.Fatalf("score list must be nil (not enough data points)")
+	}
+}
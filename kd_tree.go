@@ -0,0 +1,148 @@
+package anomalia
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+)
+
+// kdTree is a k-dimensional tree used for nearest neighbours queries using the euclidean distance,
+// or the maximum norm when maxNorm is set.
+type kdTree struct {
+	points  [][]float64
+	root    *kdNode
+	maxNorm bool
+}
+
+type kdNode struct {
+	index       int
+	axis        int
+	left, right *kdNode
+}
+
+// neighbor holds the index of a neighbour with its squared distance.
+type neighbor struct {
+	index    int
+	distance float64
+}
+
+// neighborHeap is a max heap of neighbours on the distance.
+type neighborHeap []neighbor
+
+func (h neighborHeap) Len() int            { return len(h) }
+func (h neighborHeap) Less(i, j int) bool  { return h[i].distance > h[j].distance }
+func (h neighborHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *neighborHeap) Push(x interface{}) { *h = append(*h, x.(neighbor)) }
+func (h *neighborHeap) Pop() interface{} {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
+}
+
+func newKDTree(points [][]float64) *kdTree {
+	indices := make([]int, len(points))
+	for i := range indices {
+		indices[i] = i
+	}
+	tree := &kdTree{points: points}
+	tree.root = tree.build(indices, 0)
+	return tree
+}
+
+// newMaxNormKDTree returns a k-dimensional tree whose distance is the maximum norm.
+func newMaxNormKDTree(points [][]float64) *kdTree {
+	tree := newKDTree(points)
+	tree.maxNorm = true
+	return tree
+}
+
+func (tree *kdTree) build(indices []int, depth int) *kdNode {
+	if len(indices) == 0 {
+		return nil
+	}
+	axis := depth % len(tree.points[indices[0]])
+	sort.Slice(indices, func(i, j int) bool { return tree.points[indices[i]][axis] < tree.points[indices[j]][axis] })
+
+	median := len(indices) / 2
+	return &kdNode{
+		index: indices[median],
+		axis:  axis,
+		left:  tree.build(indices[:median], depth+1),
+		right: tree.build(indices[median+1:], depth+1),
+	}
+}
+
+// nearest returns the k nearest neighbours of the point ordered by increasing distance.
+// Points for which the exclude predicate holds are skipped.
+// Euclidean distances are squared, maximum norm distances are not.
+func (tree *kdTree) nearest(point []float64, k int, exclude func(int) bool) []neighbor {
+	candidates := make(neighborHeap, 0, k+1)
+	tree.search(tree.root, point, k, exclude, &candidates)
+
+	result := make([]neighbor, len(candidates))
+	for i := len(result) - 1; i >= 0; i-- {
+		result[i] = heap.Pop(&candidates).(neighbor)
+	}
+	return result
+}
+
+func (tree *kdTree) search(node *kdNode, point []float64, k int, exclude func(int) bool, candidates *neighborHeap) {
+	if node == nil {
+		return
+	}
+
+	if !exclude(node.index) {
+		distance := tree.distance(point, tree.points[node.index])
+		if candidates.Len() < k {
+			heap.Push(candidates, neighbor{node.index, distance})
+		} else if distance < (*candidates)[0].distance {
+			(*candidates)[0] = neighbor{node.index, distance}
+			heap.Fix(candidates, 0)
+		}
+	}
+
+	delta := point[node.axis] - tree.points[node.index][node.axis]
+	near, far := node.left, node.right
+	if delta > 0 {
+		near, far = far, near
+	}
+	tree.search(near, point, k, exclude, candidates)
+
+	// The other side can only contain closer points if the splitting plane is closer than the farthest candidate
+	if candidates.Len() < k || tree.planeDistance(delta) < (*candidates)[0].distance {
+		tree.search(far, point, k, exclude, candidates)
+	}
+}
+
+func (tree *kdTree) distance(a, b []float64) float64 {
+	if tree.maxNorm {
+		return maxNormDistance(a, b)
+	}
+	return squaredDistance(a, b)
+}
+
+// planeDistance returns the distance to a splitting plane at the given offset along its axis.
+func (tree *kdTree) planeDistance(delta float64) float64 {
+	if tree.maxNorm {
+		return math.Abs(delta)
+	}
+	return delta * delta
+}
+
+func maxNormDistance(a, b []float64) float64 {
+	max := 0.0
+	for i := range a {
+		max = math.Max(max, math.Abs(a[i]-b[i]))
+	}
+	return max
+}
+
+func squaredDistance(a, b []float64) float64 {
+	sum := 0.0
+	for i := range a {
+		d := a[i] - b[i]
+		sum += d * d
+	}
+	return sum
+}
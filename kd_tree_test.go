@@ -0,0 +1,58 @@
+package anomalia
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestKDTreeNearestMatchesBruteForce(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	points := make([][]float64, 300)
+	for i := range points {
+		points[i] = []float64{random.Float64(), random.Float64(), random.Float64()}
+	}
+
+	tree := newKDTree(points)
+	exclude := func(j int) bool { return j == 0 }
+	found := tree.nearest(points[0], 5, exclude)
+
+	distances := make([]float64, 0, len(points)-1)
+	for j := 1; j < len(points); j++ {
+		distances = append(distances, squaredDistance(points[0], points[j]))
+	}
+	sort.Float64s(distances)
+
+	for i, n := range found {
+		if n.distance != distances[i] {
+			t.Fatalf("expected %v, got %v", distances[:5], found)
+		}
+	}
+}
+
+func TestMaxNormKDTreeNearestMatchesBruteForce(t *testing.T) {
+	random := rand.New(rand.NewSource(2))
+	points := make([][]float64, 300)
+	for i := range points {
+		points[i] = []float64{random.NormFloat64(), random.NormFloat64()}
+	}
+
+	tree := newMaxNormKDTree(points)
+	for _, i := range []int{0, 42, 299} {
+		found := tree.nearest(points[i], 4, func(j int) bool { return j == i })
+
+		distances := make([]float64, 0, len(points)-1)
+		for j := range points {
+			if j != i {
+				distances = append(distances, maxNormDistance(points[i], points[j]))
+			}
+		}
+		sort.Float64s(distances)
+
+		for k, n := range found {
+			if n.distance != distances[k] {
+				t.Fatalf("expected %v, got %v", distances[:4], found)
+			}
+		}
+	}
+}
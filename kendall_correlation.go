@@ -0,0 +1,157 @@
+package anomalia
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// KendallCorrelation holds the Kendall tau-b correlation algorithm configuration.
+// It measures the ordinal association between the current and target time series by counting
+// concordant and discordant pairs, and it is more reliable than Spearman on small samples with many ties.
+//
+// The coefficient is computed in O(n log n) using Knight's merge sort algorithm.
+// For details, check: https://en.wikipedia.org/wiki/Kendall_rank_correlation_coefficient
+type KendallCorrelation struct {
+	current, target *TimeSeries
+}
+
+// NewKendallCorrelation returns an instance of the kendall correlation struct.
+func NewKendallCorrelation(current, target *TimeSeries) *KendallCorrelation {
+	return &KendallCorrelation{current, target}
+}
+
+// Run runs the kendall correlation on the current and target time series.
+// It returns the tau-b coefficient which always has a value between -1 and +1.
+func (kc *KendallCorrelation) Run() float64 {
+	tau, _ := kc.compute()
+	return tau
+}
+
+// PValue returns the two-sided p-value of the hypothesis that there is no association,
+// using the normal approximation with tie correction.
+func (kc *KendallCorrelation) PValue() float64 {
+	_, pValue := kc.compute()
+	return pValue
+}
+
+// Test runs the kendall correlation along with its significance.
+// The confidence interval uses the Fisher z-transformation with the Fieller, Hartley and Pearson variance.
+func (kc *KendallCorrelation) Test(level float64) CorrelationResult {
+	tau, pValue := kc.compute()
+	n := kc.current.Size()
+	result := CorrelationResult{Coefficient: tau, PValue: pValue, SampleSize: n, Level: level}
+	result.Lower, result.Upper = fisherInterval(tau, n, level, 0.437, 4)
+	return result
+}
+
+func (kc *KendallCorrelation) compute() (float64, float64) {
+	n := kc.current.Size()
+	pairs := make([][2]float64, n)
+	for i := range pairs {
+		pairs[i] = [2]float64{kc.current.Values[i], kc.target.Values[i]}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i][0] != pairs[j][0] {
+			return pairs[i][0] < pairs[j][0]
+		}
+		return pairs[i][1] < pairs[j][1]
+	})
+
+	xTies := tieGroups(n, func(i, j int) bool { return pairs[i][0] == pairs[j][0] })
+	jointTies := tieGroups(n, func(i, j int) bool { return pairs[i] == pairs[j] })
+
+	ys := make([]float64, n)
+	for i, pair := range pairs {
+		ys[i] = pair[1]
+	}
+	swaps := mergeSortSwaps(ys, make([]float64, n))
+	yTies := tieGroups(n, func(i, j int) bool { return ys[i] == ys[j] })
+
+	pairCount := float64(n*(n-1)) / 2
+	xTied, yTied, jointTied := tiedPairs(xTies), tiedPairs(yTies), tiedPairs(jointTies)
+	score := pairCount - xTied - yTied + jointTied - 2*float64(swaps)
+
+	denom := math.Sqrt((pairCount - xTied) * (pairCount - yTied))
+	if denom == 0 {
+		return 0.0, 1.0
+	}
+	tau := score / denom
+
+	// Variance of the score under independence, corrected for ties
+	nf := float64(n)
+	variance := (nf*(nf-1)*(2*nf+5) - tieSum(xTies, func(t float64) float64 { return t * (t - 1) * (2*t + 5) }) -
+		tieSum(yTies, func(t float64) float64 { return t * (t - 1) * (2*t + 5) })) / 18
+	variance += tieSum(xTies, func(t float64) float64 { return t * (t - 1) }) *
+		tieSum(yTies, func(t float64) float64 { return t * (t - 1) }) / (2 * nf * (nf - 1))
+	if n > 2 {
+		variance += tieSum(xTies, func(t float64) float64 { return t * (t - 1) * (t - 2) }) *
+			tieSum(yTies, func(t float64) float64 { return t * (t - 1) * (t - 2) }) / (9 * nf * (nf - 1) * (nf - 2))
+	}
+	if variance <= 0 {
+		return tau, 1.0
+	}
+	return tau, math.Erfc(math.Abs(score) / math.Sqrt(2*variance))
+}
+
+func (kc *KendallCorrelation) sanityCheck() error {
+	if kc.current.Size() < 2 || kc.current.Size() != kc.target.Size() {
+		return errors.New("current and/or target series have an invalid dimension")
+	}
+	return nil
+}
+
+// tieGroups returns the sizes of the groups of consecutive equal elements among the first n sorted ones.
+func tieGroups(n int, equal func(i, j int) bool) []int {
+	var groups []int
+	for i := 0; i < n; {
+		j := i + 1
+		for j < n && equal(i, j) {
+			j++
+		}
+		if j-i > 1 {
+			groups = append(groups, j-i)
+		}
+		i = j
+	}
+	return groups
+}
+
+func tiedPairs(groups []int) float64 {
+	return tieSum(groups, func(t float64) float64 { return t * (t - 1) / 2 })
+}
+
+func tieSum(groups []int, fn func(float64) float64) (sum float64) {
+	for _, size := range groups {
+		sum += fn(float64(size))
+	}
+	return
+}
+
+// mergeSortSwaps sorts the values in place and returns the number of swaps (inversions) a bubble sort would need.
+func mergeSortSwaps(values, buffer []float64) int {
+	n := len(values)
+	if n < 2 {
+		return 0
+	}
+
+	middle := n / 2
+	swaps := mergeSortSwaps(values[:middle], buffer[:middle]) + mergeSortSwaps(values[middle:], buffer[middle:])
+
+	i, j, k := 0, middle, 0
+	for i < middle && j < n {
+		if values[j] < values[i] {
+			buffer[k] = values[j]
+			swaps += middle - i
+			j++
+		} else {
+			buffer[k] = values[i]
+			i++
+		}
+		k++
+	}
+	k += copy(buffer[k:], values[i:middle])
+	copy(buffer[k:], values[j:])
+	copy(values, buffer)
+	return swaps
+}
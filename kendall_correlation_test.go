@@ -0,0 +1,65 @@
+package anomalia
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// bruteForceKendall computes tau-b by comparing every pair.
+func bruteForceKendall(x, y []float64) float64 {
+	var concordant, discordant, xOnly, yOnly float64
+	for i := 0; i < len(x); i++ {
+		for j := i + 1; j < len(x); j++ {
+			dx, dy := x[i]-x[j], y[i]-y[j]
+			switch {
+			case dx == 0 && dy == 0:
+			case dx == 0:
+				xOnly++
+			case dy == 0:
+				yOnly++
+			case dx*dy > 0:
+				concordant++
+			default:
+				discordant++
+			}
+		}
+	}
+	return (concordant - discordant) / math.Sqrt((concordant+discordant+xOnly)*(concordant+discordant+yOnly))
+}
+
+func TestKendallCorrelationWithTies(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	x, y := make([]float64, 200), make([]float64, 200)
+	for i := range x {
+		x[i] = float64(rng.Intn(10))
+		y[i] = float64(rng.Intn(5)) + x[i]/3
+	}
+	timestamps := make([]float64, len(x))
+
+	expected := bruteForceKendall(x, y)
+	actual := NewKendallCorrelation(NewTimeSeries(timestamps, x), NewTimeSeries(timestamps, y)).Run()
+	if math.Abs(actual-expected) > 1e-12 {
+		t.Fatalf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestKendallCorrelationPValue(t *testing.T) {
+	timestamps := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	kendall := NewKendallCorrelation(NewTimeSeries(timestamps, timestamps), NewTimeSeries(timestamps, timestamps))
+
+	if tau := kendall.Run(); tau != 1 {
+		t.Fatalf("expected perfect correlation, got %v", tau)
+	}
+	if pValue := kendall.PValue(); math.Abs(pValue-5.699e-5) > 1e-7 {
+		t.Fatalf("expected a p-value of 5.699e-5, got %v", pValue)
+	}
+}
+
+func TestKendallCorrelationWithConstantSeries(t *testing.T) {
+	timestamps := []float64{1, 2, 3, 4}
+	kendall := NewKendallCorrelation(NewTimeSeries(timestamps, []float64{1, 1, 1, 1}), NewTimeSeries(timestamps, timestamps))
+	if tau, pValue := kendall.compute(); tau != 0 || pValue != 1 {
+		t.Fatalf("expected no correlation, got %v (p=%v)", tau, pValue)
+	}
+}
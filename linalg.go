@@ -0,0 +1,206 @@
+package anomalia
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+func newMatrix(rows, cols int) [][]float64 {
+	m := make([][]float64, rows)
+	for i := range m {
+		m[i] = make([]float64, cols)
+	}
+	return m
+}
+
+func multiplyMatrices(a, b [][]float64) [][]float64 {
+	product := newMatrix(len(a), len(b[0]))
+	for i := range a {
+		for k, value := range a[i] {
+			if value == 0 {
+				continue
+			}
+			for j := range b[k] {
+				product[i][j] += value * b[k][j]
+			}
+		}
+	}
+	return product
+}
+
+func transpose(m [][]float64) [][]float64 {
+	transposed := newMatrix(len(m[0]), len(m))
+	for i := range m {
+		for j := range m[i] {
+			transposed[j][i] = m[i][j]
+		}
+	}
+	return transposed
+}
+
+// covarianceMatrix returns the (population) covariance matrix of the rows of the data.
+func covarianceMatrix(data [][]float64, means []float64) [][]float64 {
+	dimension := len(means)
+	covariance := newMatrix(dimension, dimension)
+	for _, row := range data {
+		for i := 0; i < dimension; i++ {
+			for j := i; j < dimension; j++ {
+				covariance[i][j] += (row[i] - means[i]) * (row[j] - means[j])
+			}
+		}
+	}
+	for i := 0; i < dimension; i++ {
+		for j := i; j < dimension; j++ {
+			covariance[i][j] /= float64(len(data))
+			covariance[j][i] = covariance[i][j]
+		}
+	}
+	return covariance
+}
+
+// cholesky returns the lower triangular matrix L such that m = L L'.
+func cholesky(m [][]float64) ([][]float64, error) {
+	n := len(m)
+	lower := newMatrix(n, n)
+	for i := 0; i < n; i++ {
+		for j := 0; j <= i; j++ {
+			sum := m[i][j]
+			for k := 0; k < j; k++ {
+				sum -= lower[i][k] * lower[j][k]
+			}
+			if i == j {
+				if sum <= 0 {
+					return nil, errors.New("matrix is not positive definite")
+				}
+				lower[i][i] = math.Sqrt(sum)
+			} else {
+				lower[i][j] = sum / lower[j][j]
+			}
+		}
+	}
+	return lower, nil
+}
+
+// choleskySolve solves L L' x = b.
+func choleskySolve(lower [][]float64, b []float64) []float64 {
+	n := len(b)
+	y := make([]float64, n)
+	for i := 0; i < n; i++ {
+		sum := b[i]
+		for k := 0; k < i; k++ {
+			sum -= lower[i][k] * y[k]
+		}
+		y[i] = sum / lower[i][i]
+	}
+	x := make([]float64, n)
+	for i := n - 1; i >= 0; i-- {
+		sum := y[i]
+		for k := i + 1; k < n; k++ {
+			sum -= lower[k][i] * x[k]
+		}
+		x[i] = sum / lower[i][i]
+	}
+	return x
+}
+
+// symmetricEigen returns the eigenvalues of the symmetric matrix in decreasing order with their
+// eigenvectors (as columns) using the cyclic Jacobi method.
+func symmetricEigen(m [][]float64) ([]float64, [][]float64) {
+	n := len(m)
+	a := newMatrix(n, n)
+	vectors := newMatrix(n, n)
+	for i := range a {
+		copy(a[i], m[i])
+		vectors[i][i] = 1
+	}
+
+	for sweep := 0; sweep < 100; sweep++ {
+		offDiagonal := 0.0
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				offDiagonal += a[i][j] * a[i][j]
+			}
+		}
+		if offDiagonal < 1e-22 {
+			break
+		}
+
+		for p := 0; p < n; p++ {
+			for q := p + 1; q < n; q++ {
+				if math.Abs(a[p][q]) < 1e-300 {
+					continue
+				}
+				theta := (a[q][q] - a[p][p]) / (2 * a[p][q])
+				t := math.Copysign(1, theta) / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				c := 1 / math.Sqrt(t*t+1)
+				s := t * c
+
+				for k := 0; k < n; k++ {
+					akp, akq := a[k][p], a[k][q]
+					a[k][p], a[k][q] = c*akp-s*akq, s*akp+c*akq
+				}
+				for k := 0; k < n; k++ {
+					apk, aqk := a[p][k], a[q][k]
+					a[p][k], a[q][k] = c*apk-s*aqk, s*apk+c*aqk
+				}
+				for k := 0; k < n; k++ {
+					vkp, vkq := vectors[k][p], vectors[k][q]
+					vectors[k][p], vectors[k][q] = c*vkp-s*vkq, s*vkp+c*vkq
+				}
+			}
+		}
+	}
+
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return a[order[i]][order[i]] > a[order[j]][order[j]] })
+
+	values := make([]float64, n)
+	sorted := newMatrix(n, n)
+	for col, idx := range order {
+		values[col] = a[idx][idx]
+		for row := 0; row < n; row++ {
+			sorted[row][col] = vectors[row][idx]
+		}
+	}
+	return values, sorted
+}
+
+// leastSquares fits the ordinary least squares regression of y on the design rows
+// using the normal equations and returns the coefficients with the residual sum of squares.
+func leastSquares(design [][]float64, y []float64) ([]float64, float64, error) {
+	k := len(design[0])
+	gram, moments := newMatrix(k, k), make([]float64, k)
+	for row, x := range design {
+		for i := 0; i < k; i++ {
+			moments[i] += x[i] * y[row]
+			for j := 0; j <= i; j++ {
+				gram[i][j] += x[i] * x[j]
+			}
+		}
+	}
+	for i := 0; i < k; i++ {
+		for j := 0; j < i; j++ {
+			gram[j][i] = gram[i][j]
+		}
+	}
+
+	lower, err := cholesky(gram)
+	if err != nil {
+		return nil, 0, err
+	}
+	coefficients := choleskySolve(lower, moments)
+
+	rss := 0.0
+	for row, x := range design {
+		residual := y[row]
+		for i, value := range x {
+			residual -= coefficients[i] * value
+		}
+		rss += residual * residual
+	}
+	return coefficients, rss, nil
+}
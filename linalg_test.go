@@ -0,0 +1,59 @@
+package anomalia
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCholeskySolve(t *testing.T) {
+	m := [][]float64{{4, 2}, {2, 3}}
+	lower, err := cholesky(m)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	x := choleskySolve(lower, []float64{2, 1})
+	if math.Abs(4*x[0]+2*x[1]-2) > 1e-12 || math.Abs(2*x[0]+3*x[1]-1) > 1e-12 {
+		t.Fatalf("invalid solution %v", x)
+	}
+
+	if _, err := cholesky([][]float64{{1, 2}, {2, 1}}); err == nil {
+		t.Fatalf("must fail when matrix is not positive definite")
+	}
+}
+
+func TestSymmetricEigen(t *testing.T) {
+	m := [][]float64{{2, 1, 0}, {1, 2, 0}, {0, 0, 1}}
+	values, vectors := symmetricEigen(m)
+
+	expected := []float64{3, 1, 1}
+	for i := range expected {
+		if math.Abs(values[i]-expected[i]) > 1e-9 {
+			t.Fatalf("expected %v, got %v", expected, values)
+		}
+	}
+
+	// M v = lambda v
+	for col := range values {
+		for row := range m {
+			product := 0.0
+			for k := range m {
+				product += m[row][k] * vectors[k][col]
+			}
+			if math.Abs(product-values[col]*vectors[row][col]) > 1e-9 {
+				t.Fatalf("invalid eigenvector %d", col)
+			}
+		}
+	}
+}
+
+func TestLeastSquares(t *testing.T) {
+	design := [][]float64{{1, 0}, {1, 1}, {1, 2}, {1, 3}}
+	coefficients, rss, err := leastSquares(design, []float64{1, 3, 5, 7})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs(coefficients[0]-1) > 1e-9 || math.Abs(coefficients[1]-2) > 1e-9 || rss > 1e-12 {
+		t.Fatalf("expected y = 1 + 2x, got %v (rss=%v)", coefficients, rss)
+	}
+}
@@ -0,0 +1,102 @@
+package anomalia
+
+import "math"
+
+// loess returns the local linear regression of the values (observed at positions 0 to len(values) - 1)
+// evaluated at the given positions, which may lie outside of the observed range.
+// Each regression uses the span nearest points, weighted by the tricube function of their distance
+// and by the optional robustness weights.
+func loess(values, weights []float64, span int, positions []float64) []float64 {
+	return mapSlice(positions, func(x float64) float64 {
+		return loessAt(values, weights, span, x)
+	})
+}
+
+func loessAt(values, weights []float64, span int, x float64) float64 {
+	n := len(values)
+	q := minInt(maxInt(span, 1), n)
+
+	// Slide the window of q points until it holds the nearest points to x
+	lo := minInt(maxInt(int(math.Floor(x))-q/2, 0), n-q)
+	for lo > 0 && x-float64(lo-1) < float64(lo+q-1)-x {
+		lo--
+	}
+	for lo+q < n && float64(lo+q)-x < x-float64(lo) {
+		lo++
+	}
+	hi := lo + q - 1
+
+	// The bandwidth grows beyond the farthest point when the span is larger than the values
+	h := math.Max(x-float64(lo), float64(hi)-x)
+	if span > n {
+		h += float64(span-n) / 2
+	}
+
+	weight := func(j int) float64 {
+		w := 1.0
+		if h > 0 {
+			d := math.Abs(float64(j)-x) / h
+			if d >= 1 {
+				return 0.0
+			}
+			w = math.Pow(1-d*d*d, 3)
+		}
+		if weights != nil {
+			w *= weights[j]
+		}
+		return w
+	}
+
+	var sumW, sumX, sumY float64
+	for j := lo; j <= hi; j++ {
+		w := weight(j)
+		sumW += w
+		sumX += w * float64(j)
+		sumY += w * values[j]
+	}
+	if sumW <= 0 {
+		return Average(values[lo : hi+1])
+	}
+	meanX, meanY := sumX/sumW, sumY/sumW
+
+	var sumXX, sumXY float64
+	for j := lo; j <= hi; j++ {
+		w := weight(j)
+		dx := float64(j) - meanX
+		sumXX += w * dx * dx
+		sumXY += w * dx * (values[j] - meanY)
+	}
+	if sumXX <= 1e-12*float64(q*q) {
+		return meanY
+	}
+	return meanY + sumXY/sumXX*(x-meanX)
+}
+
+// slidingAverage returns the average of every window of the values,
+// so that the result has len(values) - windowSize + 1 points.
+func slidingAverage(values []float64, windowSize int) []float64 {
+	if windowSize > len(values) {
+		return []float64{}
+	}
+	averages := make([]float64, len(values)-windowSize+1)
+	sum := 0.0
+	for i, value := range values {
+		sum += value
+		if i >= windowSize {
+			sum -= values[i-windowSize]
+		}
+		if i >= windowSize-1 {
+			averages[i-windowSize+1] = sum / float64(windowSize)
+		}
+	}
+	return averages
+}
+
+// positionsOf returns the positions from start to end (both included).
+func positionsOf(start, end int) []float64 {
+	positions := make([]float64, 0, maxInt(end-start+1, 0))
+	for x := start; x <= end; x++ {
+		positions = append(positions, float64(x))
+	}
+	return positions
+}
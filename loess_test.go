@@ -0,0 +1,27 @@
+package anomalia
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLoessReproducesLines(t *testing.T) {
+	values := make([]float64, 20)
+	for i := range values {
+		values[i] = 3 + 0.5*float64(i)
+	}
+
+	smoothed := loess(values, nil, 7, positionsOf(-2, 21))
+	for i, value := range smoothed {
+		if expected := 3 + 0.5*float64(i-2); math.Abs(value-expected) > 1e-9 {
+			t.Fatalf("expected %v at position %d, got %v", expected, i-2, value)
+		}
+	}
+}
+
+func TestSlidingAverage(t *testing.T) {
+	averages := slidingAverage([]float64{1, 2, 3, 4, 5}, 3)
+	if len(averages) != 3 || averages[0] != 2 || averages[2] != 4 {
+		t.Fatalf("unexpected sliding averages %v", averages)
+	}
+}
@@ -0,0 +1,82 @@
+package anomalia
+
+import (
+	"errors"
+	"math"
+)
+
+// Mahalanobis holds the Mahalanobis distance algorithm configuration.
+//
+// Each point is scored by its distance to the mean of all points, accounting for the covariance
+// between dimensions, so that points which are individually normal but jointly unusual stand out.
+type Mahalanobis struct {
+	regularization float64
+}
+
+// NewMahalanobis returns an instance of the Mahalanobis distance algorithm.
+func NewMahalanobis() *Mahalanobis {
+	return &Mahalanobis{regularization: 1e-6}
+}
+
+// Regularization sets the ridge added to the covariance diagonal, relative to its average variance.
+func (m *Mahalanobis) Regularization(r float64) *Mahalanobis {
+	m.regularization = r
+	return m
+}
+
+// Run runs the Mahalanobis distance algorithm over the multivariate time series.
+func (m *Mahalanobis) Run(mts *MultiTimeSeries) *MultivariateScoreList {
+	scoreList, _ := m.computeScores(mts)
+	return scoreList
+}
+
+// computeScores scores each point by its Mahalanobis distance.
+// The contribution of dimension j is z_j (Σ⁻¹z)_j / d², which sums up to 1 but can be negative
+// when correlated dimensions partially cancel each other out.
+func (m *Mahalanobis) computeScores(mts *MultiTimeSeries) (*MultivariateScoreList, error) {
+	if mts.Size() <= mts.Dimension() {
+		return nil, errors.New("time series must have more points than dimensions")
+	}
+
+	means, _ := mts.columnStats()
+	covariance := covarianceMatrix(mts.Values, means)
+
+	trace := 0.0
+	for d := range covariance {
+		trace += covariance[d][d]
+	}
+	ridge := math.Max(m.regularization*trace/float64(mts.Dimension()), 1e-12)
+	for d := range covariance {
+		covariance[d][d] += ridge
+	}
+
+	lower, err := cholesky(covariance)
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make([]float64, mts.Size())
+	contributions := make([][]float64, mts.Size())
+	for i, row := range mts.Values {
+		centered := make([]float64, len(row))
+		for d, value := range row {
+			centered[d] = value - means[d]
+		}
+		weighted := choleskySolve(lower, centered)
+
+		parts := make([]float64, len(row))
+		squared := 0.0
+		for d := range parts {
+			parts[d] = centered[d] * weighted[d]
+			squared += parts[d]
+		}
+		scores[i] = math.Sqrt(math.Max(squared, 0))
+		contributions[i] = shares(parts, squared)
+	}
+
+	return &MultivariateScoreList{
+		ScoreList:     &ScoreList{mts.Timestamps, scores},
+		Names:         mts.Names,
+		Contributions: contributions,
+	}, nil
+}
@@ -0,0 +1,42 @@
+package anomalia
+
+import "testing"
+
+func TestMahalanobisDetectsJointAnomaly(t *testing.T) {
+	mts := generateCorrelatedMultiTimeSeries(300)
+	// Both values are individually within range, but memory no longer follows cpu
+	mts.Values[150][0], mts.Values[150][1] = 65, 70
+
+	scoreList := NewMahalanobis().Run(mts)
+	if scoreList == nil {
+		t.Fatalf("score list must not be nil")
+	}
+	if top := scoreList.TopK(1); top.Timestamps[0] != 150 {
+		t.Fatalf("expected the anomaly at 150, got %v", top.Timestamps[0])
+	}
+
+	sum := 0.0
+	for _, contribution := range scoreList.Contributions[150] {
+		sum += contribution
+	}
+	if sum < 0.999 || sum > 1.001 {
+		t.Fatalf("contributions must sum up to 1, got %v", sum)
+	}
+}
+
+func TestMahalanobisReportsTopContributor(t *testing.T) {
+	mts := generateCorrelatedMultiTimeSeries(300)
+	mts.Values[100][2] = 160
+
+	scoreList := NewMahalanobis().Run(mts)
+	if name := scoreList.TopContributor(100); name != "latency" {
+		t.Fatalf("expected latency to drive the score, got %s", name)
+	}
+}
+
+func TestMahalanobisWithTooFewPoints(t *testing.T) {
+	mts := generateCorrelatedMultiTimeSeries(3)
+	if _, err := NewMahalanobis().computeScores(mts); err == nil {
+		t.Fatalf("must fail when there are not enough points")
+	}
+}
@@ -43,6 +43,40 @@ func Stdev(input []float64) float64 {
 	return math.Pow(variance, 0.5)
 }
 
+// Median returns the median of the input
+func Median(input []float64) float64 {
+	sorted := sortedCopy(input)
+	length := len(sorted)
+	mid := length / 2
+
+	if length%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// Percentile returns the p-th percentile (p within [0, 100]) of the input using linear interpolation
+func Percentile(input []float64, p float64) float64 {
+	sorted := sortedCopy(input)
+	position := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(position))
+	upper := int(math.Ceil(position))
+	if lower == upper {
+		return sorted[lower]
+	}
+	return sorted[lower] + (position-float64(lower))*(sorted[upper]-sorted[lower])
+}
+
+// MedianAbsoluteDeviation returns the median of the absolute deviations from the median of the input
+func MedianAbsoluteDeviation(input []float64) float64 {
+	median := Median(input)
+	deviations := make([]float64, len(input))
+	for i, value := range input {
+		deviations[i] = math.Abs(value - median)
+	}
+	return Median(deviations)
+}
+
 // RoundFloat rounds float to closest int
 func RoundFloat(num float64) int {
 	return int(num + math.Copysign(0.5, num))
@@ -71,6 +105,34 @@ func Cdf(mean, stdev float64) func(float64) float64 {
 	}
 }
 
+// Quantile returns the quantile function (inverse of the cumulative distribution function)
+func Quantile(mean, stdev float64) func(float64) float64 {
+	return func(p float64) float64 {
+		return mean + stdev*math.Sqrt2*math.Erfinv(2*p-1)
+	}
+}
+
+// StudentTCdf returns the cumulative distribution function of the Student's t-distribution
+func StudentTCdf(degreesOfFreedom float64) func(float64) float64 {
+	return func(t float64) float64 {
+		tail := 0.5 * regularizedIncompleteBeta(degreesOfFreedom/2, 0.5, degreesOfFreedom/(degreesOfFreedom+t*t))
+		if t > 0 {
+			return 1 - tail
+		}
+		return tail
+	}
+}
+
+// FCdf returns the cumulative distribution function of the F-distribution
+func FCdf(d1, d2 float64) func(float64) float64 {
+	return func(x float64) float64 {
+		if x <= 0 {
+			return 0.0
+		}
+		return regularizedIncompleteBeta(d1/2, d2/2, d1*x/(d1*x+d2))
+	}
+}
+
 // Erf is the guassian error function
 func Erf(x float64) float64 {
 	// Constants
@@ -127,3 +189,74 @@ func AbsInt(x int) int {
 	}
 	return x
 }
+
+// regularizedIncompleteBeta returns I_x(a, b) using the continued fraction from Numerical Recipes.
+func regularizedIncompleteBeta(a, b, x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+
+	lbetaA, _ := math.Lgamma(a)
+	lbetaB, _ := math.Lgamma(b)
+	lbetaAB, _ := math.Lgamma(a + b)
+	front := math.Exp(lbetaAB - lbetaA - lbetaB + a*math.Log(x) + b*math.Log(1-x))
+
+	// The continued fraction converges quickly for x < (a+1)/(a+b+2), otherwise use the symmetry relation
+	if x > (a+1)/(a+b+2) {
+		return 1 - front*betaContinuedFraction(b, a, 1-x)/b
+	}
+	return front * betaContinuedFraction(a, b, x) / a
+}
+
+func betaContinuedFraction(a, b, x float64) float64 {
+	const (
+		maxIterations = 300
+		epsilon       = 1e-15
+		tiny          = 1e-300
+	)
+
+	c, d := 1.0, 1-(a+b)*x/(a+1)
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	h := d
+
+	for m := 1; m <= maxIterations; m++ {
+		mf := float64(m)
+		for _, numerator := range []float64{
+			mf * (b - mf) * x / ((a + 2*mf - 1) * (a + 2*mf)),
+			-(a + mf) * (a + b + mf) * x / ((a + 2*mf) * (a + 2*mf + 1)),
+		} {
+			d = 1 + numerator*d
+			if math.Abs(d) < tiny {
+				d = tiny
+			}
+			c = 1 + numerator/c
+			if math.Abs(c) < tiny {
+				c = tiny
+			}
+			d = 1 / d
+			h *= d * c
+		}
+		if math.Abs(d*c-1) < epsilon {
+			break
+		}
+	}
+	return h
+}
+
+// digamma returns the logarithmic derivative of the gamma function for x > 0.
+func digamma(x float64) float64 {
+	result := 0.0
+	// Shift x so that the asymptotic expansion is accurate
+	for x < 6 {
+		result -= 1 / x
+		x++
+	}
+	inverse := 1 / (x * x)
+	return result + math.Log(x) - 0.5/x - inverse*(1.0/12-inverse*(1.0/120-inverse*(1.0/252-inverse*(1.0/240-inverse/132))))
+}
@@ -39,6 +39,30 @@ func TestStdev(t *testing.T) {
 	}
 }
 
+func TestMedianOfInput(t *testing.T) {
+	actual := Median([]float64{5, 1, 3, 2})
+	expected := 2.5
+	if actual != expected {
+		t.Fatalf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	actual := Percentile(input, 25)
+	expected := 3.25
+	if actual != expected {
+		t.Fatalf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestMedianAbsoluteDeviation(t *testing.T) {
+	actual := MedianAbsoluteDeviation([]float64{1, 1, 2, 2, 4, 6, 9})
+	expected := 1.0
+	if actual != expected {
+		t.Fatalf("expected %v, got %v", expected, actual)
+	}
+}
+
 func TestRoundFloat(t *testing.T) {
 	actual := RoundFloat(0.5)
 	expected := 1
@@ -71,6 +95,35 @@ func TestCdf(t *testing.T) {
 	}
 }
 
+func TestQuantile(t *testing.T) {
+	actual := Quantile(0.0, 1.0)(0.975)
+	expected := 1.959963984540054
+	if math.Abs(actual-expected) > 1e-12 {
+		t.Fatalf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestStudentTCdf(t *testing.T) {
+	actual := StudentTCdf(10)(2.0)
+	expected := 0.963305982614283
+	if math.Abs(actual-expected) > 1e-9 {
+		t.Fatalf("expected %v, got %v", expected, actual)
+	}
+
+	actual = StudentTCdf(10)(-2.0)
+	if math.Abs(actual-(1-expected)) > 1e-9 {
+		t.Fatalf("expected %v, got %v", 1-expected, actual)
+	}
+}
+
+func TestFCdf(t *testing.T) {
+	actual := FCdf(3, 20)(3.1)
+	expected := 0.9499
+	if math.Abs(actual-expected) > 1e-3 {
+		t.Fatalf("expected %v, got %v", expected, actual)
+	}
+}
+
 func TestErf(t *testing.T) {
 	actual := Erf(1.0)
 	expected := 0.8427006897475899
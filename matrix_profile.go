@@ -0,0 +1,344 @@
+package anomalia
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// MatrixProfile holds the matrix profile algorithm configuration.
+//
+// The matrix profile stores, for every subsequence of the time series, the z-normalized
+// euclidean distance to its nearest non-trivial neighbour. High values are discords (unusual shapes)
+// while low values are motifs (repeated shapes).
+// The exact profile is computed with STOMP, seeded by the FFT based MASS algorithm,
+// and the anytime mode uses SCRIMP which evaluates a random fraction of the diagonals.
+// For more details, check: https://www.cs.ucr.edu/~eamonn/MatrixProfile.html
+type MatrixProfile struct {
+	windowSize    int
+	exclusionZone int
+	discords      int
+	fraction      float64
+	seed          int64
+}
+
+// Profile holds the computed matrix profile.
+// Distances[i] is the distance of the subsequence starting at i to its nearest neighbour Indices[i].
+type Profile struct {
+	WindowSize    int
+	Timestamps    []float64
+	Distances     []float64
+	Indices       []int
+	exclusionZone int
+}
+
+// Subsequence holds a subsequence of the time series with its nearest neighbour.
+type Subsequence struct {
+	Index             int
+	Timestamp         float64
+	NeighborIndex     int
+	NeighborTimestamp float64
+	Distance          float64
+}
+
+// NewMatrixProfile returns MatrixProfile instance.
+func NewMatrixProfile() *MatrixProfile {
+	return &MatrixProfile{
+		windowSize: 16,
+		fraction:   1.0,
+		seed:       1,
+	}
+}
+
+// WindowSize sets the subsequence length (defaults to 16).
+func (mp *MatrixProfile) WindowSize(size int) *MatrixProfile {
+	mp.windowSize = size
+	return mp
+}
+
+// ExclusionZone sets the number of neighbouring subsequences ignored as trivial matches (defaults to a quarter of the window size).
+func (mp *MatrixProfile) ExclusionZone(size int) *MatrixProfile {
+	mp.exclusionZone = size
+	return mp
+}
+
+// Discords restricts the scores to the top-k discords (defaults to 0 which keeps the whole profile).
+func (mp *MatrixProfile) Discords(k int) *MatrixProfile {
+	mp.discords = k
+	return mp
+}
+
+// Anytime enables the anytime mode which only evaluates the specified fraction of the distance matrix
+// in random order. The seed makes the approximation deterministic.
+func (mp *MatrixProfile) Anytime(fraction float64, seed int64) *MatrixProfile {
+	mp.fraction = fraction
+	mp.seed = seed
+	return mp
+}
+
+// Run runs the matrix profile algorithm over the time series.
+// The score of each point is the profile value of the subsequence starting at it.
+func (mp *MatrixProfile) Run(timeSeries *TimeSeries) *ScoreList {
+	scoreList, _ := mp.computeScores(timeSeries)
+	return scoreList
+}
+
+// Profile computes the matrix profile of the time series.
+func (mp *MatrixProfile) Profile(timeSeries *TimeSeries) (*Profile, error) {
+	if err := mp.sanityCheck(timeSeries); err != nil {
+		return nil, err
+	}
+
+	exclusionZone := mp.exclusionZone
+	if exclusionZone <= 0 {
+		exclusionZone = int(math.Ceil(float64(mp.windowSize) / 4))
+	}
+
+	var distances []float64
+	var indices []int
+	if mp.fraction >= 1 {
+		distances, indices = stomp(timeSeries.Values, mp.windowSize, exclusionZone)
+	} else {
+		distances, indices = scrimp(timeSeries.Values, mp.windowSize, exclusionZone, mp.fraction, mp.seed)
+	}
+
+	return &Profile{
+		WindowSize:    mp.windowSize,
+		Timestamps:    timeSeries.Timestamps[:len(distances)],
+		Distances:     distances,
+		Indices:       indices,
+		exclusionZone: exclusionZone,
+	}, nil
+}
+
+func (mp *MatrixProfile) computeScores(timeSeries *TimeSeries) (*ScoreList, error) {
+	profile, err := mp.Profile(timeSeries)
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make([]float64, timeSeries.Size())
+	if mp.discords > 0 {
+		for _, discord := range profile.Discords(mp.discords) {
+			scores[discord.Index] = discord.Distance
+		}
+	} else {
+		for idx, distance := range profile.Distances {
+			if !math.IsInf(distance, 1) {
+				scores[idx] = distance
+			}
+		}
+	}
+	return &ScoreList{timeSeries.Timestamps, scores}, nil
+}
+
+func (mp *MatrixProfile) sanityCheck(timeSeries *TimeSeries) error {
+	if mp.windowSize < 4 {
+		return errors.New("window size must be at least 4")
+	}
+	if timeSeries.Size() < 2*mp.windowSize {
+		return errors.New("not enough data points")
+	}
+	if mp.fraction <= 0 {
+		return errors.New("anytime fraction must be positive")
+	}
+	return nil
+}
+
+// Discords returns the top-k non overlapping subsequences with the highest distance to their nearest neighbour.
+func (p *Profile) Discords(k int) []Subsequence {
+	order := p.sortedIndices(func(a, b float64) bool { return a > b })
+	return p.pick(order, k, false)
+}
+
+// Motifs returns the top-k non overlapping subsequences with the lowest distance to their nearest neighbour.
+// Each motif pair is reported once.
+func (p *Profile) Motifs(k int) []Subsequence {
+	order := p.sortedIndices(func(a, b float64) bool { return a < b })
+	return p.pick(order, k, true)
+}
+
+func (p *Profile) sortedIndices(less func(float64, float64) bool) []int {
+	order := make([]int, 0, len(p.Distances))
+	for idx, distance := range p.Distances {
+		if !math.IsInf(distance, 1) && p.Indices[idx] >= 0 {
+			order = append(order, idx)
+		}
+	}
+	sort.SliceStable(order, func(i, j int) bool { return less(p.Distances[order[i]], p.Distances[order[j]]) })
+	return order
+}
+
+// pick returns the first k non overlapping subsequences in order.
+// When pairs is set, the nearest neighbour of each result is excluded too, so that a pair is reported once.
+func (p *Profile) pick(order []int, k int, pairs bool) []Subsequence {
+	var (
+		result = make([]Subsequence, 0, k)
+		taken  = make([]int, 0, 2*k)
+	)
+
+	overlaps := func(idx int) bool {
+		for _, other := range taken {
+			if AbsInt(idx-other) < p.WindowSize {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, idx := range order {
+		if len(result) >= k {
+			break
+		}
+		neighbor := p.Indices[idx]
+		if overlaps(idx) || (pairs && overlaps(neighbor)) {
+			continue
+		}
+		result = append(result, Subsequence{
+			Index:             idx,
+			Timestamp:         p.Timestamps[idx],
+			NeighborIndex:     neighbor,
+			NeighborTimestamp: p.Timestamps[neighbor],
+			Distance:          p.Distances[idx],
+		})
+		taken = append(taken, idx)
+		if pairs {
+			taken = append(taken, neighbor)
+		}
+	}
+	return result
+}
+
+// Mass returns the distance profile of the query, i.e. the z-normalized euclidean distance
+// between the query and every subsequence of the series, using the FFT based MASS algorithm.
+func Mass(query, series []float64) []float64 {
+	m := len(query)
+	means, stdevs := movingMeanStdev(series, m)
+	queryMean, queryStdev := Average(query), Stdev(query)
+	products := slidingDotProduct(query, series)
+
+	distances := make([]float64, len(products))
+	for i, product := range products {
+		distances[i] = znormDistance(product, m, queryMean, queryStdev, means[i], stdevs[i])
+	}
+	return distances
+}
+
+// slidingDotProduct returns the dot product between the query and every subsequence of the series.
+func slidingDotProduct(query, series []float64) []float64 {
+	m, n := len(query), len(series)
+	reversed := make([]float64, m)
+	for i, value := range query {
+		reversed[m-1-i] = value
+	}
+	product := convolve(series, reversed)
+	return product[m-1 : n]
+}
+
+func movingMeanStdev(series []float64, m int) ([]float64, []float64) {
+	size := len(series) - m + 1
+	means, stdevs := make([]float64, size), make([]float64, size)
+	sum, squares := 0.0, 0.0
+	for i, value := range series {
+		sum += value
+		squares += value * value
+		if i >= m {
+			sum -= series[i-m]
+			squares -= series[i-m] * series[i-m]
+		}
+		if i >= m-1 {
+			mean := sum / float64(m)
+			means[i-m+1] = mean
+			stdevs[i-m+1] = math.Sqrt(math.Max(squares/float64(m)-mean*mean, 0))
+		}
+	}
+	return means, stdevs
+}
+
+func znormDistance(product float64, m int, meanA, stdevA, meanB, stdevB float64) float64 {
+	const epsilon = 1e-12
+	switch {
+	case stdevA < epsilon && stdevB < epsilon:
+		return 0.0
+	case stdevA < epsilon || stdevB < epsilon:
+		return math.Sqrt(float64(m))
+	}
+	correlation := (product - float64(m)*meanA*meanB) / (float64(m) * stdevA * stdevB)
+	return math.Sqrt(math.Max(2*float64(m)*(1-correlation), 0))
+}
+
+func newEmptyProfile(size int) ([]float64, []int) {
+	distances, indices := make([]float64, size), make([]int, size)
+	for i := range distances {
+		distances[i] = math.Inf(1)
+		indices[i] = -1
+	}
+	return distances, indices
+}
+
+// stomp computes the exact matrix profile by updating the dot products row by row.
+func stomp(series []float64, m, exclusionZone int) ([]float64, []int) {
+	size := len(series) - m + 1
+	means, stdevs := movingMeanStdev(series, m)
+	distances, indices := newEmptyProfile(size)
+
+	firstRow := slidingDotProduct(series[:m], series)
+	row := copySlice(firstRow)
+	for i := 0; i < size; i++ {
+		if i > 0 {
+			for j := size - 1; j > 0; j-- {
+				row[j] = row[j-1] - series[j-1]*series[i-1] + series[j+m-1]*series[i+m-1]
+			}
+			row[0] = firstRow[i]
+		}
+
+		for j := 0; j < size; j++ {
+			if AbsInt(i-j) <= exclusionZone {
+				continue
+			}
+			distance := znormDistance(row[j], m, means[i], stdevs[i], means[j], stdevs[j])
+			if distance < distances[i] {
+				distances[i], indices[i] = distance, j
+			}
+		}
+	}
+	return distances, indices
+}
+
+// scrimp approximates the matrix profile by evaluating a random fraction of the diagonals.
+func scrimp(series []float64, m, exclusionZone int, fraction float64, seed int64) ([]float64, []int) {
+	size := len(series) - m + 1
+	means, stdevs := movingMeanStdev(series, m)
+	distances, indices := newEmptyProfile(size)
+
+	diagonals := make([]int, 0, size)
+	for k := exclusionZone + 1; k < size; k++ {
+		diagonals = append(diagonals, k)
+	}
+	rand.New(rand.NewSource(seed)).Shuffle(len(diagonals), func(i, j int) {
+		diagonals[i], diagonals[j] = diagonals[j], diagonals[i]
+	})
+	count := int(math.Ceil(fraction * float64(len(diagonals))))
+	if count > len(diagonals) {
+		count = len(diagonals)
+	}
+
+	for _, k := range diagonals[:count] {
+		product := sumOfProducts(series[:m], series[k:k+m])
+		for i := 0; i+k < size; i++ {
+			j := i + k
+			if i > 0 {
+				product += series[i+m-1]*series[j+m-1] - series[i-1]*series[j-1]
+			}
+			distance := znormDistance(product, m, means[i], stdevs[i], means[j], stdevs[j])
+			if distance < distances[i] {
+				distances[i], indices[i] = distance, j
+			}
+			if distance < distances[j] {
+				distances[j], indices[j] = distance, i
+			}
+		}
+	}
+	return distances, indices
+}
@@ -0,0 +1,139 @@
+package anomalia
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestRunMatrixProfile(t *testing.T) {
+	timeSeries := generatePeriodicTimeSeries(400, 20)
+	for i := 200; i < 205; i++ {
+		timeSeries.Values[i] = -timeSeries.Values[i]
+	}
+
+	scoreList := NewMatrixProfile().WindowSize(20).Discords(1).Run(timeSeries)
+	if scoreList == nil {
+		t.Fatalf("score list cannot be nil")
+	}
+
+	if len(scoreList.Scores) != timeSeries.Size() {
+		t.Fatalf("score list must have the same dimension as original time series")
+	}
+
+	if idx := indexOf(scoreList.Scores, scoreList.Max()); idx < 200-20 || idx > 205 {
+		t.Fatalf("discord must overlap the distorted cycle, got index %d", idx)
+	}
+}
+
+func TestMatrixProfileAnytimeConvergesToExact(t *testing.T) {
+	timeSeries := generateFakeTimeSeries(200)
+	exact, _ := NewMatrixProfile().WindowSize(10).Profile(timeSeries)
+	anytime, _ := NewMatrixProfile().WindowSize(10).Anytime(0.999999, 3).Profile(timeSeries)
+
+	for i := range exact.Distances {
+		if math.Abs(exact.Distances[i]-anytime.Distances[i]) > 1e-6 {
+			t.Fatalf("expected %v, got %v at index %d", exact.Distances[i], anytime.Distances[i], i)
+		}
+	}
+}
+
+func TestMatrixProfilePartialAnytimeIsAnUpperBound(t *testing.T) {
+	timeSeries := generatePeriodicTimeSeries(400, 20)
+	rng := rand.New(rand.NewSource(5))
+	for i := range timeSeries.Values {
+		timeSeries.Values[i] += 0.05 * rng.NormFloat64()
+	}
+	for i := 200; i < 205; i++ {
+		timeSeries.Values[i] = -timeSeries.Values[i]
+	}
+
+	exact, _ := NewMatrixProfile().WindowSize(20).Profile(timeSeries)
+	top := exact.Discords(1)[0].Index
+
+	matches := 0
+	for seed := int64(1); seed <= 10; seed++ {
+		anytime, err := NewMatrixProfile().WindowSize(20).Anytime(0.3, seed).Profile(timeSeries)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for i, distance := range anytime.Distances {
+			if math.IsInf(distance, 0) || math.IsNaN(distance) {
+				t.Fatalf("seed %d: partial distance at index %d must be finite, got %v", seed, i, distance)
+			}
+			if distance < exact.Distances[i]-1e-9 {
+				t.Fatalf("seed %d: partial distance %v is below the exact %v at index %d", seed, distance, exact.Distances[i], i)
+			}
+		}
+		if AbsInt(anytime.Discords(1)[0].Index-top) < 20 {
+			matches++
+		}
+	}
+	if matches < 7 {
+		t.Fatalf("expected the top discord to usually match the exact one, matched %d out of 10", matches)
+	}
+}
+
+func TestMatrixProfileMotifs(t *testing.T) {
+	timeSeries := generatePeriodicTimeSeries(200, 25)
+	profile, err := NewMatrixProfile().WindowSize(25).Profile(timeSeries)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	motifs := profile.Motifs(1)
+	if len(motifs) != 1 {
+		t.Fatalf("expected one motif, got %d", len(motifs))
+	}
+	if motifs[0].Distance > 1e-3 {
+		t.Fatalf("repeated cycles must have a near zero distance, got %v", motifs[0].Distance)
+	}
+}
+
+func TestProfileDiscordsIgnoreNeighborsOfOtherDiscords(t *testing.T) {
+	// The second discord (60) sits next to the nearest neighbour (62) of the first one (10)
+	profile := &Profile{
+		WindowSize: 5,
+		Timestamps: make([]float64, 100),
+		Distances:  make([]float64, 100),
+		Indices:    make([]int, 100),
+	}
+	for i := range profile.Indices {
+		profile.Timestamps[i] = float64(i)
+		profile.Distances[i] = 1
+		profile.Indices[i] = (i + 50) % 100
+	}
+	profile.Distances[10], profile.Indices[10] = 9, 62
+	profile.Distances[60], profile.Indices[60] = 8, 30
+
+	discords := profile.Discords(2)
+	if len(discords) != 2 || discords[0].Index != 10 || discords[1].Index != 60 {
+		t.Fatalf("expected discords at 10 and 60, got %+v", discords)
+	}
+
+	motifs := profile.Motifs(2)
+	if len(motifs) != 2 || AbsInt(motifs[1].Index-motifs[0].NeighborIndex) < profile.WindowSize {
+		t.Fatalf("motif pairs must not overlap, got %+v", motifs)
+	}
+}
+
+func TestMassDistanceProfile(t *testing.T) {
+	series := []float64{1, 2, 3, 2, 1, 2, 3, 2, 1}
+	distances := Mass([]float64{1, 2, 3}, series)
+	if len(distances) != len(series)-2 {
+		t.Fatalf("distance profile has an invalid dimension")
+	}
+	if distances[0] > 1e-6 || distances[4] > 1e-6 {
+		t.Fatalf("exact matches must have a zero distance, got %v", distances)
+	}
+}
+
+func generatePeriodicTimeSeries(datasetSize, period int) *TimeSeries {
+	timestamps := make([]float64, datasetSize)
+	values := make([]float64, datasetSize)
+	for i := 0; i < datasetSize; i++ {
+		timestamps[i] = float64(i) + 1
+		values[i] = math.Sin(2 * math.Pi * float64(i) / float64(period))
+	}
+	return &TimeSeries{timestamps, values}
+}
@@ -0,0 +1,268 @@
+package anomalia
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// MSTL holds the Multiple Seasonal-Trend decomposition using Loess algorithm configuration.
+//
+// The MSTL algorithm extends STL to time series with several seasonalities (daily and weekly for example).
+// Each seasonal component is extracted in turn, from the shortest to the longest period, by applying STL
+// to the time series where all the other seasonal components were removed. This is repeated a few times
+// to refine the components.
+// The STL inner and outer loops follow Cleveland et al. (1990) and are implemented here.
+// The paper describing this algorithm can be found here: https://arxiv.org/abs/2107.13462
+type MSTL struct {
+	periods           []int
+	widths            []int
+	iterations        int
+	innerIterations   int
+	robustIterations  int
+	scoring           STLScoring
+	scoringWindowSize int
+}
+
+// MSTLDecomposition holds the components of a time series decomposed with multiple seasonalities.
+// Seasonals[i] is the seasonal component of Periods[i].
+type MSTLDecomposition struct {
+	Periods   []int
+	Trend     *TimeSeries
+	Seasonals []*TimeSeries
+	Residual  *TimeSeries
+}
+
+// NewMSTL returns an instance of the MSTL struct.
+func NewMSTL(periods ...int) *MSTL {
+	return &MSTL{
+		periods:          periods,
+		iterations:       2,
+		innerIterations:  2,
+		robustIterations: 0,
+		scoring:          RawResidual,
+	}
+}
+
+// Periods sets the seasonal periods in data points.
+func (m *MSTL) Periods(periods ...int) *MSTL {
+	m.periods = periods
+	return m
+}
+
+// Widths sets the seasonal smoothing width of each period, in number of cycles.
+// When not set, widths default to 11, 15, 19 and so on from the shortest to the longest period.
+func (m *MSTL) Widths(widths ...int) *MSTL {
+	m.widths = widths
+	return m
+}
+
+// Iterations sets the number of times each seasonal component is refined (defaults to 2).
+func (m *MSTL) Iterations(n int) *MSTL {
+	m.iterations = n
+	return m
+}
+
+// InnerIterations sets the number of iterations of each STL decomposition (defaults to 2).
+func (m *MSTL) InnerIterations(n int) *MSTL {
+	m.innerIterations = n
+	return m
+}
+
+// RobustIterations sets the number of robust iterations of each STL decomposition (defaults to 0).
+func (m *MSTL) RobustIterations(n int) *MSTL {
+	m.robustIterations = n
+	return m
+}
+
+// Scoring sets how residuals are turned into scores (defaults to RawResidual).
+func (m *MSTL) Scoring(scoring STLScoring) *MSTL {
+	m.scoring = scoring
+	return m
+}
+
+// ScoringWindowSize sets the rolling window size used by the RollingZScore scoring.
+// When 0 (default), the longest period is used.
+func (m *MSTL) ScoringWindowSize(size int) *MSTL {
+	m.scoringWindowSize = size
+	return m
+}
+
+// Run runs the MSTL algorithm over the time series.
+func (m *MSTL) Run(timeSeries *TimeSeries) *ScoreList {
+	scoreList, _ := m.computeScores(timeSeries)
+	return scoreList
+}
+
+// Decompose decomposes the time series into its trend, seasonal components and residual.
+func (m *MSTL) Decompose(timeSeries *TimeSeries) (*MSTLDecomposition, error) {
+	if err := m.sanityCheck(timeSeries); err != nil {
+		return nil, err
+	}
+
+	// Seasonal components are extracted from the shortest to the longest period
+	order := make([]int, len(m.periods))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return m.periods[order[i]] < m.periods[order[j]] })
+
+	seasonals := make([][]float64, len(m.periods))
+	for i := range seasonals {
+		seasonals[i] = make([]float64, timeSeries.Size())
+	}
+	deseasonalized := copySlice(timeSeries.Values)
+
+	var trend []float64
+	for iteration := 0; iteration < m.iterations; iteration++ {
+		for rank, i := range order {
+			// Add back the current estimate of the seasonal component before refining it
+			for j := range deseasonalized {
+				deseasonalized[j] += seasonals[i][j]
+			}
+
+			seasonals[i], trend = stlDecompose(deseasonalized, m.periods[i], m.width(i, rank),
+				m.innerIterations, m.robustIterations)
+			for j := range deseasonalized {
+				deseasonalized[j] -= seasonals[i][j]
+			}
+		}
+	}
+
+	residual := mapSliceWithIndex(deseasonalized, func(j int, value float64) float64 {
+		return value - trend[j]
+	})
+
+	decomposition := &MSTLDecomposition{
+		Periods:   m.periods,
+		Trend:     NewTimeSeries(timeSeries.Timestamps, trend),
+		Seasonals: make([]*TimeSeries, len(m.periods)),
+		Residual:  NewTimeSeries(timeSeries.Timestamps, residual),
+	}
+	for i, seasonal := range seasonals {
+		decomposition.Seasonals[i] = NewTimeSeries(timeSeries.Timestamps, seasonal)
+	}
+	return decomposition, nil
+}
+
+func (m *MSTL) computeScores(timeSeries *TimeSeries) (*ScoreList, error) {
+	decomposition, err := m.Decompose(timeSeries)
+	if err != nil {
+		return nil, err
+	}
+
+	windowSize := m.scoringWindowSize
+	if windowSize <= 0 {
+		for _, period := range m.periods {
+			windowSize = maxInt(windowSize, period)
+		}
+	}
+	scores := scoreResiduals(decomposition.Residual.Values, m.scoring, 0.0, windowSize)
+	return &ScoreList{timeSeries.Timestamps, scores}, nil
+}
+
+// width returns the seasonal smoothing width of the ith period, which is the rank-th shortest one.
+func (m *MSTL) width(i, rank int) int {
+	if i < len(m.widths) && m.widths[i] > 0 {
+		return m.widths[i]
+	}
+	return 11 + 4*rank
+}
+
+func (m *MSTL) sanityCheck(timeSeries *TimeSeries) error {
+	if len(m.periods) == 0 {
+		return errors.New("at least one period is required")
+	}
+	if m.iterations < 1 || m.innerIterations < 1 {
+		return errors.New("number of iterations must be positive")
+	}
+	if m.robustIterations < 0 {
+		return errors.New("number of robust iterations must not be negative")
+	}
+	for _, width := range m.widths {
+		if width > 0 && width < 3 {
+			return errors.New("each width must be at least 3")
+		}
+	}
+	for _, period := range m.periods {
+		if period < 2 {
+			return errors.New("each period must be at least 2")
+		}
+		if timeSeries.Size() < 2*period {
+			return errors.New("not enough data points")
+		}
+	}
+	return nil
+}
+
+// stlDecompose returns the seasonal and trend components of the values with the given period,
+// where the seasonal component is smoothed over seasonalWidth cycles.
+func stlDecompose(values []float64, period, seasonalWidth, innerIterations, robustIterations int) ([]float64, []float64) {
+	n := len(values)
+	trendWidth := nextOdd(int(math.Ceil(1.5 * float64(period) / (1 - 1.5/float64(seasonalWidth)))))
+	lowPassWidth := nextOdd(period)
+	positions := positionsOf(0, n-1)
+
+	seasonal, trend := make([]float64, n), make([]float64, n)
+	var weights []float64
+	for outer := 0; outer <= robustIterations; outer++ {
+		for inner := 0; inner < innerIterations; inner++ {
+			detrended := mapSliceWithIndex(values, func(j int, value float64) float64 { return value - trend[j] })
+
+			// Smooth each cycle-subseries, extended by one period on both sides
+			cycle := make([]float64, n+2*period)
+			for p := 0; p < period && p < n; p++ {
+				var subseries, subweights []float64
+				for j := p; j < n; j += period {
+					subseries = append(subseries, detrended[j])
+					if weights != nil {
+						subweights = append(subweights, weights[j])
+					}
+				}
+				smoothed := loess(subseries, subweights, seasonalWidth, positionsOf(-1, len(subseries)))
+				for k, value := range smoothed {
+					cycle[p+k*period] = value
+				}
+			}
+
+			// Remove the low frequencies leaking into the smoothed cycle-subseries
+			lowPass := slidingAverage(slidingAverage(slidingAverage(cycle, period), period), 3)
+			lowPass = loess(lowPass, nil, lowPassWidth, positions)
+			for j := range seasonal {
+				seasonal[j] = cycle[j+period] - lowPass[j]
+			}
+
+			deseasonalized := mapSliceWithIndex(values, func(j int, value float64) float64 { return value - seasonal[j] })
+			trend = loess(deseasonalized, weights, trendWidth, positions)
+		}
+
+		if outer < robustIterations {
+			weights = robustnessWeights(mapSliceWithIndex(values, func(j int, value float64) float64 {
+				return value - seasonal[j] - trend[j]
+			}))
+		}
+	}
+	return seasonal, trend
+}
+
+// robustnessWeights returns the bisquare weights of the residuals, scaled by six times their median absolute value.
+func robustnessWeights(residuals []float64) []float64 {
+	h := 6 * Median(mapSlice(residuals, math.Abs))
+	return mapSlice(residuals, func(residual float64) float64 {
+		if h == 0 {
+			return 1.0
+		}
+		u := math.Abs(residual) / h
+		if u >= 1 {
+			return 0.0
+		}
+		return (1 - u*u) * (1 - u*u)
+	})
+}
+
+func nextOdd(n int) int {
+	if n%2 == 0 {
+		return n + 1
+	}
+	return n
+}
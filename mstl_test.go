@@ -0,0 +1,75 @@
+package anomalia
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDecomposeWithMSTL(t *testing.T) {
+	periods := []int{12, 24}
+	ts := generateMultiSeasonalTimeSeries(24*20, periods...)
+	decomposition, err := NewMSTL(periods...).Decompose(ts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(decomposition.Seasonals) != 2 {
+		t.Fatalf("expected one seasonal component per period")
+	}
+
+	// Each seasonal component must recover its generating sine
+	for j, period := range periods {
+		sum := 0.0
+		for i, value := range decomposition.Seasonals[j].Values {
+			expected := float64(j+1) * math.Sin(2*math.Pi*float64(i)/float64(period))
+			sum += (value - expected) * (value - expected)
+		}
+		rmse := math.Sqrt(sum / float64(ts.Size()))
+		if rmse > 0.1 {
+			t.Fatalf("seasonal component of period %d is too far from its sine (rmse %v)", period, rmse)
+		}
+	}
+
+	// The trend must follow the generated slope
+	sum := 0.0
+	for i, value := range decomposition.Trend.Values {
+		expected := 0.01 * float64(i)
+		sum += (value - expected) * (value - expected)
+	}
+	if rmse := math.Sqrt(sum / float64(ts.Size())); rmse > 0.05 {
+		t.Fatalf("trend is too far from the generated slope (rmse %v)", rmse)
+	}
+}
+
+func TestRunWithMSTL(t *testing.T) {
+	ts := generateMultiSeasonalTimeSeries(24*7*4, 24, 24*7)
+	scoreList := NewMSTL(24, 24*7).Scoring(AbsoluteResidual).Run(ts)
+	if scoreList == nil {
+		t.Fatalf("score list cannot be nil")
+	}
+
+	if len(scoreList.Scores) != ts.Size() {
+		t.Fatalf("score list must have the same dimension as original time series")
+	}
+
+	if scoreList = NewMSTL(24, 24*7).RobustIterations(2).Run(ts); scoreList == nil {
+		t.Fatalf("score list cannot be nil with robust iterations")
+	}
+
+	if scoreList = NewMSTL(24, 24*7*5).Run(ts); scoreList != nil {
+		t.Fatalf("score list must be nil (not enough data points)")
+	}
+}
+
+func generateMultiSeasonalTimeSeries(datasetSize int, periods ...int) *TimeSeries {
+	timestamps := make([]float64, datasetSize)
+	values := make([]float64, datasetSize)
+	for i := 0; i < datasetSize; i++ {
+		timestamps[i] = float64(i) + 1
+		values[i] = 0.01 * float64(i)
+		for j, period := range periods {
+			values[i] += float64(j+1) * math.Sin(2*math.Pi*float64(i)/float64(period))
+		}
+	}
+	return &TimeSeries{timestamps, values}
+}
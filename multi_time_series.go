@@ -0,0 +1,102 @@
+package anomalia
+
+import (
+	"math"
+	"sort"
+)
+
+// MultiTimeSeries holds several named time series aligned on the same timestamps.
+type MultiTimeSeries struct {
+	Names      []string
+	Timestamps []float64
+	Values     [][]float64 // Values[i][d] is the value of dimension d at timestamp i
+}
+
+// NewMultiTimeSeries creates a multivariate time series from named time series.
+// The series are aligned on the union of their timestamps; a missing value takes the
+// last known value of its series (or the first one, before the series starts).
+func NewMultiTimeSeries(names []string, series ...*TimeSeries) *MultiTimeSeries {
+	if len(names) != len(series) {
+		panic("names and series must have the same size")
+	}
+
+	union := make(map[float64]bool)
+	for _, ts := range series {
+		for _, timestamp := range ts.Timestamps {
+			union[timestamp] = true
+		}
+	}
+	timestamps := make([]float64, 0, len(union))
+	for timestamp := range union {
+		timestamps = append(timestamps, timestamp)
+	}
+	sort.Float64s(timestamps)
+
+	values := newMatrix(len(timestamps), len(series))
+	for d, ts := range series {
+		if ts.Size() == 0 {
+			panic("time series must not be empty")
+		}
+		zipped := ts.Zip()
+		known := zipped[ts.EarliestTimestamp()]
+		for i, timestamp := range timestamps {
+			if value, ok := zipped[timestamp]; ok {
+				known = value
+			}
+			values[i][d] = known
+		}
+	}
+
+	return &MultiTimeSeries{
+		Names:      names,
+		Timestamps: timestamps,
+		Values:     values,
+	}
+}
+
+// Size returns the number of timestamps.
+func (mts *MultiTimeSeries) Size() int {
+	return len(mts.Timestamps)
+}
+
+// Dimension returns the number of series.
+func (mts *MultiTimeSeries) Dimension() int {
+	return len(mts.Names)
+}
+
+// Series returns the aligned time series with the given name or nil if none exists.
+func (mts *MultiTimeSeries) Series(name string) *TimeSeries {
+	for d, n := range mts.Names {
+		if n == name {
+			values := make([]float64, mts.Size())
+			for i := range values {
+				values[i] = mts.Values[i][d]
+			}
+			return NewTimeSeries(copySlice(mts.Timestamps), values)
+		}
+	}
+	return nil
+}
+
+// columnStats returns the mean and the standard deviation of every dimension.
+func (mts *MultiTimeSeries) columnStats() ([]float64, []float64) {
+	means := make([]float64, mts.Dimension())
+	stdevs := make([]float64, mts.Dimension())
+	for _, row := range mts.Values {
+		for d, value := range row {
+			means[d] += value
+		}
+	}
+	for d := range means {
+		means[d] /= float64(mts.Size())
+	}
+	for _, row := range mts.Values {
+		for d, value := range row {
+			stdevs[d] += (value - means[d]) * (value - means[d])
+		}
+	}
+	for d := range stdevs {
+		stdevs[d] = math.Sqrt(stdevs[d] / float64(mts.Size()))
+	}
+	return means, stdevs
+}
@@ -0,0 +1,51 @@
+package anomalia
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// generateCorrelatedMultiTimeSeries generates cpu and memory series following each other
+// and an independent latency series.
+func generateCorrelatedMultiTimeSeries(size int) *MultiTimeSeries {
+	rng := rand.New(rand.NewSource(7))
+	timestamps := make([]float64, size)
+	cpu, memory, latency := make([]float64, size), make([]float64, size), make([]float64, size)
+	for i := 0; i < size; i++ {
+		timestamps[i] = float64(i)
+		cpu[i] = 50 + 20*math.Sin(float64(i)/10) + rng.NormFloat64()
+		memory[i] = 2*cpu[i] + rng.NormFloat64()
+		latency[i] = 100 + 5*rng.NormFloat64()
+	}
+	return NewMultiTimeSeries(
+		[]string{"cpu", "memory", "latency"},
+		NewTimeSeries(timestamps, cpu),
+		NewTimeSeries(copySlice(timestamps), memory),
+		NewTimeSeries(copySlice(timestamps), latency),
+	)
+}
+
+func TestNewMultiTimeSeriesAlignsTimestamps(t *testing.T) {
+	mts := NewMultiTimeSeries(
+		[]string{"a", "b"},
+		NewTimeSeries([]float64{1, 2, 4}, []float64{10, 20, 40}),
+		NewTimeSeries([]float64{2, 3}, []float64{200, 300}),
+	)
+
+	if mts.Size() != 4 || mts.Dimension() != 2 {
+		t.Fatalf("expected 4 timestamps and 2 dimensions, got %d and %d", mts.Size(), mts.Dimension())
+	}
+
+	b := mts.Series("b")
+	expected := []float64{200, 200, 300, 300}
+	for i, value := range expected {
+		if b.Values[i] != value {
+			t.Fatalf("expected %v, got %v", expected, b.Values)
+		}
+	}
+
+	if mts.Series("c") != nil {
+		t.Fatalf("must return nil for unknown series")
+	}
+}
@@ -0,0 +1,42 @@
+package anomalia
+
+// MultivariateAlgorithm is the base interface of algorithms scoring several series jointly.
+type MultivariateAlgorithm interface {
+	Run(*MultiTimeSeries) *MultivariateScoreList
+	computeScores(*MultiTimeSeries) (*MultivariateScoreList, error)
+}
+
+// MultivariateScoreList holds the joint scores along with the contribution of each dimension to them.
+type MultivariateScoreList struct {
+	*ScoreList
+	Names         []string
+	Contributions [][]float64 // Contributions[i][d] is the share of dimension d in the score at index i
+}
+
+// Contribution returns the contribution of every dimension to the score at the given index.
+func (ms *MultivariateScoreList) Contribution(idx int) map[string]float64 {
+	contributions := make(map[string]float64, len(ms.Names))
+	for d, name := range ms.Names {
+		contributions[name] = ms.Contributions[idx][d]
+	}
+	return contributions
+}
+
+// TopContributor returns the name of the dimension which drove the score at the given index the most.
+func (ms *MultivariateScoreList) TopContributor(idx int) string {
+	top := 0
+	for d, contribution := range ms.Contributions[idx] {
+		if contribution > ms.Contributions[idx][top] {
+			top = d
+		}
+	}
+	return ms.Names[top]
+}
+
+// shares divides the parts by their total so that they sum up to 1.
+func shares(parts []float64, total float64) []float64 {
+	if total == 0 {
+		return make([]float64, len(parts))
+	}
+	return mapSlice(parts, func(part float64) float64 { return part / total })
+}
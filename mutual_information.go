@@ -0,0 +1,220 @@
+package anomalia
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// MIEstimator type checker for the mutual information estimator
+type MIEstimator int32
+
+const (
+	// KSG estimates mutual information from the k nearest neighbors distances (Kraskov, Stögbauer and Grassberger).
+	KSG MIEstimator = iota
+
+	// Binned estimates mutual information from a two-dimensional histogram.
+	Binned
+)
+
+// MutualInformation holds the mutual information algorithm configuration.
+//
+// Mutual information measures how much knowing the current time series reduces the uncertainty
+// about the target time series, which captures non-linear and non-monotonic dependencies
+// that Pearson and Spearman correlations miss.
+type MutualInformation struct {
+	current, target *TimeSeries
+	estimator       MIEstimator
+	neighbors       int
+	bins            int
+	resamples       int
+	seed            int64
+}
+
+// NewMutualInformation returns an instance of the mutual information struct.
+func NewMutualInformation(current, target *TimeSeries) *MutualInformation {
+	return &MutualInformation{
+		current:   current,
+		target:    target,
+		estimator: KSG,
+		neighbors: 3,
+		resamples: 200,
+		seed:      1,
+	}
+}
+
+// Estimator sets the mutual information estimator (defaults to KSG).
+func (mi *MutualInformation) Estimator(estimator MIEstimator) *MutualInformation {
+	mi.estimator = estimator
+	return mi
+}
+
+// Neighbors sets the number of neighbors used by the KSG estimator (defaults to 3).
+func (mi *MutualInformation) Neighbors(k int) *MutualInformation {
+	mi.neighbors = k
+	return mi
+}
+
+// Bins sets the number of bins per dimension used by the Binned estimator.
+// When 0 (default), it follows Sturges' rule.
+func (mi *MutualInformation) Bins(n int) *MutualInformation {
+	mi.bins = n
+	return mi
+}
+
+// Resamples sets the number of permutations used by Test (defaults to 200).
+func (mi *MutualInformation) Resamples(n int) *MutualInformation {
+	mi.resamples = n
+	return mi
+}
+
+// Seed sets the seed of the random source used by Test and by the KSG estimator to break ties (defaults to 1).
+func (mi *MutualInformation) Seed(seed int64) *MutualInformation {
+	mi.seed = seed
+	return mi
+}
+
+// Information returns the estimated mutual information in nats.
+func (mi *MutualInformation) Information() float64 {
+	current, target := mi.values()
+	return mi.estimate(current, target)
+}
+
+// Run returns the informational coefficient of correlation sqrt(1 - exp(-2 MI)), which has a value between 0 and 1
+// and equals the absolute Pearson coefficient for normally distributed time series.
+func (mi *MutualInformation) Run() float64 {
+	return informationCoefficient(mi.Information())
+}
+
+// Test runs the mutual information algorithm along with its significance.
+// The p-value comes from a permutation test which shuffles the target values.
+// There is no confidence interval for the coefficient, so Lower and Upper are NaN.
+func (mi *MutualInformation) Test(level float64) CorrelationResult {
+	current, target := mi.values()
+	information := mi.estimate(current, target)
+	pValue := permutationPValue(information, target, mi.resamples, rand.New(rand.NewSource(mi.seed)), func(shuffled []float64) float64 {
+		return mi.estimate(current, shuffled)
+	})
+
+	return CorrelationResult{
+		Coefficient: informationCoefficient(information),
+		PValue:      pValue,
+		SampleSize:  len(current),
+		Lower:       math.NaN(),
+		Upper:       math.NaN(),
+		Level:       level,
+	}
+}
+
+func (mi *MutualInformation) sanityCheck() error {
+	if mi.estimator == KSG && mi.neighbors < 1 {
+		return errors.New("number of neighbors must be at least 1")
+	}
+	if mi.current.Size() != mi.target.Size() || mi.current.Size() <= mi.neighbors {
+		return errors.New("current and/or target series have an invalid dimension")
+	}
+	return nil
+}
+
+// values returns the time series values, slightly jittered for the KSG estimator
+// which assumes that there are no duplicate points.
+func (mi *MutualInformation) values() ([]float64, []float64) {
+	if mi.estimator != KSG {
+		return mi.current.Values, mi.target.Values
+	}
+
+	rng := rand.New(rand.NewSource(mi.seed))
+	jitter := func(values []float64) []float64 {
+		scale := 1e-10 * math.Max(Stdev(values), 1)
+		jittered := make([]float64, len(values))
+		for i, value := range values {
+			jittered[i] = value + scale*rng.NormFloat64()
+		}
+		return jittered
+	}
+	return jitter(mi.current.Values), jitter(mi.target.Values)
+}
+
+func (mi *MutualInformation) estimate(x, y []float64) float64 {
+	if mi.estimator == Binned {
+		bins := mi.bins
+		if bins <= 0 {
+			bins = int(math.Ceil(math.Log2(float64(len(x))))) + 1
+		}
+		return binnedMutualInformation(x, y, bins)
+	}
+	return ksgMutualInformation(x, y, mi.neighbors)
+}
+
+// ksgMutualInformation implements the first KSG estimator using the maximum norm:
+// I = ψ(k) + ψ(N) - <ψ(nx + 1) + ψ(ny + 1)>.
+// The k-th neighbour of each point comes from a KD-tree, so that it runs in about O(N log N).
+func ksgMutualInformation(x, y []float64, k int) float64 {
+	n := len(x)
+	sortedX, sortedY := sortedCopy(x), sortedCopy(y)
+
+	points := make([][]float64, n)
+	for i := range points {
+		points[i] = []float64{x[i], y[i]}
+	}
+	tree := newMaxNormKDTree(points)
+
+	sum := 0.0
+	for i := 0; i < n; i++ {
+		neighbors := tree.nearest(points[i], k, func(j int) bool { return j == i })
+		epsilon := neighbors[k-1].distance
+
+		sum += digamma(float64(countWithin(sortedX, x[i], epsilon))) + digamma(float64(countWithin(sortedY, y[i], epsilon)))
+	}
+
+	return math.Max(digamma(float64(k))+digamma(float64(n))-sum/float64(n), 0)
+}
+
+// countWithin returns the number of sorted values strictly within epsilon of the value,
+// which includes the value itself and so equals nx + 1.
+func countWithin(sorted []float64, value, epsilon float64) int {
+	lower := sort.Search(len(sorted), func(i int) bool { return sorted[i] > value-epsilon })
+	upper := sort.Search(len(sorted), func(i int) bool { return sorted[i] >= value+epsilon })
+	return upper - lower
+}
+
+// binnedMutualInformation estimates mutual information from an equal width two-dimensional histogram.
+func binnedMutualInformation(x, y []float64, bins int) float64 {
+	n := float64(len(x))
+	xBins, yBins := binIndices(x, bins), binIndices(y, bins)
+
+	joint := newMatrix(bins, bins)
+	xMarginal, yMarginal := make([]float64, bins), make([]float64, bins)
+	for i := range xBins {
+		joint[xBins[i]][yBins[i]]++
+		xMarginal[xBins[i]]++
+		yMarginal[yBins[i]]++
+	}
+
+	information := 0.0
+	for i := range joint {
+		for j, count := range joint[i] {
+			if count > 0 {
+				information += count / n * math.Log(count*n/(xMarginal[i]*yMarginal[j]))
+			}
+		}
+	}
+	return information
+}
+
+func binIndices(values []float64, bins int) []int {
+	min, max := minMax(values)
+	indices := make([]int, len(values))
+	if max == min {
+		return indices
+	}
+	for i, value := range values {
+		indices[i] = minInt(int(float64(bins)*(value-min)/(max-min)), bins-1)
+	}
+	return indices
+}
+
+func informationCoefficient(information float64) float64 {
+	return math.Sqrt(1 - math.Exp(-2*information))
+}
@@ -0,0 +1,78 @@
+package anomalia
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// generateSaturatedTimeSeries generates a load and a latency which grows on both sides of the saturation point,
+// so that they are dependent but not monotonically related.
+func generateSaturatedTimeSeries(size int, seed int64) (*TimeSeries, *TimeSeries) {
+	rng := rand.New(rand.NewSource(seed))
+	timestamps, load, latency := make([]float64, size), make([]float64, size), make([]float64, size)
+	for i := range timestamps {
+		timestamps[i] = float64(i)
+		load[i] = rng.Float64()*2 - 1
+		latency[i] = load[i]*load[i] + 0.05*rng.NormFloat64()
+	}
+	return NewTimeSeries(timestamps, load), NewTimeSeries(copySlice(timestamps), latency)
+}
+
+func TestMutualInformationWithGaussianTimeSeries(t *testing.T) {
+	rng := rand.New(rand.NewSource(6))
+	size, rho := 2000, 0.8
+	timestamps, x, y := make([]float64, size), make([]float64, size), make([]float64, size)
+	for i := range timestamps {
+		timestamps[i] = float64(i)
+		x[i] = rng.NormFloat64()
+		y[i] = rho*x[i] + math.Sqrt(1-rho*rho)*rng.NormFloat64()
+	}
+
+	// The mutual information of bivariate normal variables is -log(1 - rho^2) / 2
+	expected := -math.Log(1-rho*rho) / 2
+	actual := NewMutualInformation(NewTimeSeries(timestamps, x), NewTimeSeries(timestamps, y)).Information()
+	if math.Abs(actual-expected) > 0.05 {
+		t.Fatalf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestMutualInformationDetectsNonMonotonicDependency(t *testing.T) {
+	load, latency := generateSaturatedTimeSeries(300, 1)
+
+	pearson := NewPearsonCorrelation(load, latency).Run()
+	for _, estimator := range []MIEstimator{KSG, Binned} {
+		result := NewMutualInformation(load, latency).Estimator(estimator).Resamples(50).Test(0.95)
+		if result.Coefficient < 0.5 || result.Coefficient < 2*math.Abs(pearson) || result.PValue > 0.05 {
+			t.Fatalf("expected a significant dependency with estimator %d, got %+v", estimator, result)
+		}
+	}
+}
+
+func TestMutualInformationWithIndependentTimeSeries(t *testing.T) {
+	load, _ := generateSaturatedTimeSeries(300, 1)
+	_, latency := generateSaturatedTimeSeries(300, 2)
+
+	result := NewMutualInformation(load, latency).Resamples(50).Test(0.95)
+	if result.PValue < 0.05 {
+		t.Fatalf("expected an insignificant dependency, got %+v", result)
+	}
+}
+
+func TestDigamma(t *testing.T) {
+	// psi(1) is minus the Euler-Mascheroni constant
+	if actual := digamma(1); math.Abs(actual+0.5772156649015329) > 1e-10 {
+		t.Fatalf("expected %v, got %v", -0.5772156649015329, actual)
+	}
+}
+
+func TestMutualInformationWithInvalidNeighbors(t *testing.T) {
+	load, latency := generateSaturatedTimeSeries(50, 1)
+
+	for _, k := range []float64{0, -1} {
+		_, err := NewCorrelator(load, latency).CorrelationMethod(KSGMutualInformation, []float64{k}).result()
+		if err == nil {
+			t.Fatalf("must fail with %v neighbors", k)
+		}
+	}
+}
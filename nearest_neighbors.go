@@ -0,0 +1,192 @@
+package anomalia
+
+import (
+	"errors"
+	"math"
+	"sync"
+)
+
+// DistanceMetric type checker for the distance between embedded vectors
+type DistanceMetric int32
+
+const (
+	// Euclidean represents the euclidean distance.
+	Euclidean DistanceMetric = iota
+
+	// ZNormalizedEuclidean represents the euclidean distance between z-normalized vectors,
+	// which compares shapes regardless of their offset and amplitude.
+	ZNormalizedEuclidean
+)
+
+// KNNDistance holds the k-th nearest neighbour distance algorithm configuration.
+//
+// The time series is delay embedded into vectors of consecutive values and each point is scored by the
+// distance of its vector (the one ending at the point) to the k-th nearest other vector.
+// Overlapping vectors are ignored as trivial neighbours.
+type KNNDistance struct {
+	*nearestNeighbors
+}
+
+// LocalOutlierFactor holds the Local Outlier Factor (LOF) algorithm configuration.
+//
+// The time series is delay embedded into vectors of consecutive values and each point is scored
+// by comparing the local density of its vector to the local densities of its k nearest neighbours.
+// Scores around 1 indicate normal density while higher scores indicate outliers.
+// Overlapping vectors are ignored as trivial neighbours.
+// The paper describing this algorithm can be found here: https://doi.org/10.1145/342009.335388
+type LocalOutlierFactor struct {
+	*nearestNeighbors
+}
+
+// nearestNeighbors holds the configuration shared by nearest neighbours based algorithms.
+type nearestNeighbors struct {
+	dimension int
+	k         int
+	metric    DistanceMetric
+}
+
+// NewKNNDistance returns KNNDistance instance.
+func NewKNNDistance() *KNNDistance {
+	return &KNNDistance{&nearestNeighbors{dimension: 4, k: 5, metric: Euclidean}}
+}
+
+// NewLocalOutlierFactor returns LocalOutlierFactor instance.
+func NewLocalOutlierFactor() *LocalOutlierFactor {
+	return &LocalOutlierFactor{&nearestNeighbors{dimension: 4, k: 10, metric: Euclidean}}
+}
+
+// Dimension sets the embedding dimension, i.e. the number of consecutive values per vector (defaults to 4).
+func (knn *KNNDistance) Dimension(dimension int) *KNNDistance {
+	knn.dimension = dimension
+	return knn
+}
+
+// K sets the number of nearest neighbours (defaults to 5).
+func (knn *KNNDistance) K(k int) *KNNDistance {
+	knn.k = k
+	return knn
+}
+
+// Metric sets the distance metric (defaults to Euclidean).
+func (knn *KNNDistance) Metric(metric DistanceMetric) *KNNDistance {
+	knn.metric = metric
+	return knn
+}
+
+// Run runs the k-th nearest neighbour distance algorithm over the time series.
+func (knn *KNNDistance) Run(timeSeries *TimeSeries) *ScoreList {
+	scoreList, _ := knn.computeScores(timeSeries)
+	return scoreList
+}
+
+func (knn *KNNDistance) computeScores(timeSeries *TimeSeries) (*ScoreList, error) {
+	vectors, neighbors, err := knn.neighbors(timeSeries)
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make([]float64, timeSeries.Size())
+	for i := range vectors {
+		scores[i+knn.dimension-1] = math.Sqrt(neighbors[i][len(neighbors[i])-1].distance)
+	}
+	return &ScoreList{timeSeries.Timestamps, scores}, nil
+}
+
+// Dimension sets the embedding dimension, i.e. the number of consecutive values per vector (defaults to 4).
+func (lof *LocalOutlierFactor) Dimension(dimension int) *LocalOutlierFactor {
+	lof.dimension = dimension
+	return lof
+}
+
+// K sets the number of nearest neighbours (defaults to 10).
+func (lof *LocalOutlierFactor) K(k int) *LocalOutlierFactor {
+	lof.k = k
+	return lof
+}
+
+// Metric sets the distance metric (defaults to Euclidean).
+func (lof *LocalOutlierFactor) Metric(metric DistanceMetric) *LocalOutlierFactor {
+	lof.metric = metric
+	return lof
+}
+
+// Run runs the local outlier factor algorithm over the time series.
+func (lof *LocalOutlierFactor) Run(timeSeries *TimeSeries) *ScoreList {
+	scoreList, _ := lof.computeScores(timeSeries)
+	return scoreList
+}
+
+func (lof *LocalOutlierFactor) computeScores(timeSeries *TimeSeries) (*ScoreList, error) {
+	vectors, neighbors, err := lof.neighbors(timeSeries)
+	if err != nil {
+		return nil, err
+	}
+
+	kDistances := make([]float64, len(vectors))
+	for i := range vectors {
+		kDistances[i] = math.Sqrt(neighbors[i][len(neighbors[i])-1].distance)
+	}
+
+	// Local reachability density is the inverse of the average reachability distance to the neighbours
+	densities := make([]float64, len(vectors))
+	for i := range vectors {
+		sum := 0.0
+		for _, n := range neighbors[i] {
+			sum += math.Max(kDistances[n.index], math.Sqrt(n.distance))
+		}
+		densities[i] = 1 / (sum/float64(len(neighbors[i])) + 1e-10)
+	}
+
+	scores := make([]float64, timeSeries.Size())
+	for i := range vectors {
+		sum := 0.0
+		for _, n := range neighbors[i] {
+			sum += densities[n.index]
+		}
+		scores[i+lof.dimension-1] = sum / float64(len(neighbors[i])) / densities[i]
+	}
+	return &ScoreList{timeSeries.Timestamps, scores}, nil
+}
+
+// neighbors embeds the time series and finds the k nearest neighbours of each vector.
+func (nn *nearestNeighbors) neighbors(timeSeries *TimeSeries) ([][]float64, [][]neighbor, error) {
+	if nn.dimension < 1 || nn.k < 1 {
+		return nil, nil, errors.New("dimension and k must be positive")
+	}
+
+	vectors := embedValues(timeSeries.Values, nn.dimension, nn.metric)
+	if len(vectors) < nn.k+2*nn.dimension {
+		return nil, nil, errors.New("not enough data points")
+	}
+
+	tree := newKDTree(vectors)
+	neighbors := make([][]neighbor, len(vectors))
+
+	var wg sync.WaitGroup
+	wg.Add(len(vectors))
+	for i := range vectors {
+		go func(i int) {
+			defer wg.Done()
+			neighbors[i] = tree.nearest(vectors[i], nn.k, func(j int) bool { return AbsInt(i-j) < nn.dimension })
+		}(i)
+	}
+	wg.Wait()
+	return vectors, neighbors, nil
+}
+
+// embedValues returns the delay embedding of the values: vectors of consecutive values.
+func embedValues(values []float64, dimension int, metric DistanceMetric) [][]float64 {
+	size := len(values) - dimension + 1
+	if size < 0 {
+		size = 0
+	}
+	vectors := make([][]float64, size)
+	for i := range vectors {
+		vector := copySlice(values[i : i+dimension])
+		if metric == ZNormalizedEuclidean {
+			vector = ZNormalize(vector)
+		}
+		vectors[i] = vector
+	}
+	return vectors
+}
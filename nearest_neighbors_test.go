@@ -0,0 +1,41 @@
+package anomalia
+
+import "testing"
+
+func TestRunWithKNNDistance(t *testing.T) {
+	ts := generatePeriodicTimeSeries(400, 20)
+	ts.Values[200] += 2
+
+	for _, metric := range []DistanceMetric{Euclidean, ZNormalizedEuclidean} {
+		scoreList := NewKNNDistance().Dimension(5).K(3).Metric(metric).Run(ts)
+		if scoreList == nil {
+			t.Fatalf("score list cannot be nil")
+		}
+
+		if idx := indexOf(scoreList.Scores, scoreList.Max()); idx < 200 || idx >= 205 {
+			t.Fatalf("vectors containing the spike must have the highest score, got index %d", idx)
+		}
+	}
+}
+
+func TestRunWithLocalOutlierFactor(t *testing.T) {
+	ts := generatePeriodicTimeSeries(400, 20)
+	ts.Values[300] += 2
+
+	scoreList := NewLocalOutlierFactor().Dimension(5).K(10).Run(ts)
+	if scoreList == nil {
+		t.Fatalf("score list cannot be nil")
+	}
+
+	if len(scoreList.Scores) != ts.Size() {
+		t.Fatalf("score list must have the same dimension as original time series")
+	}
+
+	if idx := indexOf(scoreList.Scores, scoreList.Max()); idx < 300 || idx >= 305 {
+		t.Fatalf("vectors containing the spike must have the highest score, got index %d", idx)
+	}
+
+	if scoreList = NewLocalOutlierFactor().Run(NewTimeSeries([]float64{1, 2, 3}, []float64{1, 2, 3})); scoreList != nil {
+		t.Fatalf("score list must be nil (not enough data points)")
+	}
+}
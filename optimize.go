@@ -0,0 +1,102 @@
+package anomalia
+
+import (
+	"math"
+	"sort"
+)
+
+const (
+	optimizationTolerance     = 1e-8
+	maxOptimizationIterations = 500
+)
+
+// minimize finds a local minimum of the function using the Nelder-Mead simplex method.
+func minimize(f func([]float64) float64, initial []float64) []float64 {
+	n := len(initial)
+	if n == 0 {
+		return initial
+	}
+
+	// Build the initial simplex around the starting point
+	simplex := make([][]float64, n+1)
+	values := make([]float64, n+1)
+	simplex[0] = copySlice(initial)
+	for i := 0; i < n; i++ {
+		vertex := copySlice(initial)
+		if vertex[i] != 0 {
+			vertex[i] *= 1.1
+		} else {
+			vertex[i] = 0.1
+		}
+		simplex[i+1] = vertex
+	}
+	for i, vertex := range simplex {
+		values[i] = f(vertex)
+	}
+
+	order := make([]int, n+1)
+	for iteration := 0; iteration < maxOptimizationIterations*n; iteration++ {
+		for i := range order {
+			order[i] = i
+		}
+		sort.Slice(order, func(i, j int) bool { return values[order[i]] < values[order[j]] })
+		best, worst, secondWorst := order[0], order[n], order[n-1]
+		if math.Abs(values[worst]-values[best]) <= optimizationTolerance*(math.Abs(values[best])+optimizationTolerance) {
+			break
+		}
+
+		// Centroid of all vertices but the worst
+		centroid := make([]float64, n)
+		for _, idx := range order[:n] {
+			for j := range centroid {
+				centroid[j] += simplex[idx][j] / float64(n)
+			}
+		}
+		along := func(coefficient float64) []float64 {
+			point := make([]float64, n)
+			for j := range point {
+				point[j] = centroid[j] + coefficient*(simplex[worst][j]-centroid[j])
+			}
+			return point
+		}
+
+		reflected := along(-1)
+		reflectedValue := f(reflected)
+		switch {
+		case reflectedValue < values[best]:
+			expanded := along(-2)
+			if expandedValue := f(expanded); expandedValue < reflectedValue {
+				simplex[worst], values[worst] = expanded, expandedValue
+			} else {
+				simplex[worst], values[worst] = reflected, reflectedValue
+			}
+		case reflectedValue < values[secondWorst]:
+			simplex[worst], values[worst] = reflected, reflectedValue
+		default:
+			contracted := along(0.5)
+			if reflectedValue < values[worst] {
+				contracted = along(-0.5)
+			}
+			if contractedValue := f(contracted); contractedValue < math.Min(values[worst], reflectedValue) {
+				simplex[worst], values[worst] = contracted, contractedValue
+				continue
+			}
+
+			// Shrink the simplex towards the best vertex
+			for _, idx := range order[1:] {
+				for j := range simplex[idx] {
+					simplex[idx][j] = simplex[best][j] + 0.5*(simplex[idx][j]-simplex[best][j])
+				}
+				values[idx] = f(simplex[idx])
+			}
+		}
+	}
+
+	best := 0
+	for i := range values {
+		if values[i] < values[best] {
+			best = i
+		}
+	}
+	return simplex[best]
+}
@@ -0,0 +1,17 @@
+package anomalia
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMinimize(t *testing.T) {
+	rosenbrock := func(x []float64) float64 {
+		return math.Pow(1-x[0], 2) + 100*math.Pow(x[1]-x[0]*x[0], 2)
+	}
+
+	minimum := minimize(rosenbrock, []float64{-1.2, 1})
+	if math.Abs(minimum[0]-1) > 1e-3 || math.Abs(minimum[1]-1) > 1e-3 {
+		t.Fatalf("expected [1 1], got %v", minimum)
+	}
+}
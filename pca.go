@@ -0,0 +1,112 @@
+package anomalia
+
+import (
+	"errors"
+	"math"
+)
+
+// PCA holds the PCA reconstruction error algorithm configuration.
+//
+// The standardized points are projected onto the principal components capturing most of the variance
+// and scored by their squared reconstruction error, so that points breaking the usual relations between
+// dimensions stand out.
+type PCA struct {
+	components        int
+	explainedVariance float64
+}
+
+// NewPCA returns an instance of the PCA reconstruction error algorithm.
+func NewPCA() *PCA {
+	return &PCA{explainedVariance: 0.9}
+}
+
+// Components sets the number of principal components kept.
+// When 0 (default), the components are chosen using the explained variance.
+func (p *PCA) Components(k int) *PCA {
+	p.components = k
+	return p
+}
+
+// ExplainedVariance sets the ratio of variance the kept components must explain (defaults to 0.9).
+func (p *PCA) ExplainedVariance(ratio float64) *PCA {
+	p.explainedVariance = ratio
+	return p
+}
+
+// Run runs the PCA reconstruction error algorithm over the multivariate time series.
+func (p *PCA) Run(mts *MultiTimeSeries) *MultivariateScoreList {
+	scoreList, _ := p.computeScores(mts)
+	return scoreList
+}
+
+func (p *PCA) computeScores(mts *MultiTimeSeries) (*MultivariateScoreList, error) {
+	if mts.Dimension() < 2 {
+		return nil, errors.New("time series must have at least two dimensions")
+	}
+	if mts.Size() < 2 {
+		return nil, errors.New("time series must have at least two points")
+	}
+
+	means, stdevs := mts.columnStats()
+	standardized := make([][]float64, mts.Size())
+	for i, row := range mts.Values {
+		standardized[i] = make([]float64, len(row))
+		for d, value := range row {
+			if stdevs[d] > 0 {
+				standardized[i][d] = (value - means[d]) / stdevs[d]
+			}
+		}
+	}
+
+	eigenvalues, eigenvectors := symmetricEigen(covarianceMatrix(standardized, make([]float64, mts.Dimension())))
+	k := p.selectComponents(eigenvalues)
+
+	scores := make([]float64, mts.Size())
+	contributions := make([][]float64, mts.Size())
+	for i, row := range standardized {
+		reconstructed := make([]float64, len(row))
+		for c := 0; c < k; c++ {
+			projection := 0.0
+			for d, value := range row {
+				projection += value * eigenvectors[d][c]
+			}
+			for d := range reconstructed {
+				reconstructed[d] += projection * eigenvectors[d][c]
+			}
+		}
+
+		errs := make([]float64, len(row))
+		for d, value := range row {
+			errs[d] = (value - reconstructed[d]) * (value - reconstructed[d])
+			scores[i] += errs[d]
+		}
+		contributions[i] = shares(errs, scores[i])
+	}
+
+	return &MultivariateScoreList{
+		ScoreList:     &ScoreList{mts.Timestamps, scores},
+		Names:         mts.Names,
+		Contributions: contributions,
+	}, nil
+}
+
+// selectComponents returns the number of kept components, always leaving at least one out
+// so that there is a residual subspace to measure errors in.
+func (p *PCA) selectComponents(eigenvalues []float64) int {
+	dimension := len(eigenvalues)
+	if p.components > 0 {
+		return minInt(p.components, dimension-1)
+	}
+
+	total := 0.0
+	for _, value := range eigenvalues {
+		total += math.Max(value, 0)
+	}
+
+	k, explained := 0, 0.0
+	for k < dimension-1 && (total == 0 || explained/total < p.explainedVariance) {
+		explained += math.Max(eigenvalues[k], 0)
+		k++
+	}
+	return maxInt(k, 1)
+}
@@ -0,0 +1,40 @@
+package anomalia
+
+import "testing"
+
+func TestPCADetectsJointAnomaly(t *testing.T) {
+	mts := generateCorrelatedMultiTimeSeries(300)
+	mts.Values[150][0], mts.Values[150][1] = 65, 70
+
+	scoreList := NewPCA().Components(2).Run(mts)
+	if scoreList == nil {
+		t.Fatalf("score list must not be nil")
+	}
+	if top := scoreList.TopK(1); top.Timestamps[0] != 150 {
+		t.Fatalf("expected the anomaly at 150, got %v", top.Timestamps[0])
+	}
+
+	contribution := scoreList.Contribution(150)
+	if contribution["latency"] > contribution["cpu"] || contribution["latency"] > contribution["memory"] {
+		t.Fatalf("expected cpu and memory to drive the score, got %v", contribution)
+	}
+}
+
+func TestPCASelectsComponentsByExplainedVariance(t *testing.T) {
+	if k := NewPCA().selectComponents([]float64{2, 0.9, 0.1}); k != 2 {
+		t.Fatalf("expected 2 components, got %d", k)
+	}
+	if k := NewPCA().ExplainedVariance(0.5).selectComponents([]float64{2, 0.9, 0.1}); k != 1 {
+		t.Fatalf("expected 1 component, got %d", k)
+	}
+	if k := NewPCA().Components(5).selectComponents([]float64{2, 0.9, 0.1}); k != 2 {
+		t.Fatalf("must leave at least one component out, got %d", k)
+	}
+}
+
+func TestPCAWithSingleDimension(t *testing.T) {
+	mts := NewMultiTimeSeries([]string{"a"}, NewTimeSeries([]float64{1, 2, 3}, []float64{1, 2, 3}))
+	if _, err := NewPCA().computeScores(mts); err == nil {
+		t.Fatalf("must fail with a single dimension")
+	}
+}
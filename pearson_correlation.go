@@ -38,6 +38,12 @@ func (pc *PearsonCorrelation) Run() float64 {
 	return (sumOfProducts(pc.current.Values, pc.target.Values) - n*currentAvg*targetAvg) / denom
 }
 
+// Test runs the pearson correlation along with its significance.
+// The p-value comes from the t-distribution and the confidence interval from the Fisher z-transformation.
+func (pc *PearsonCorrelation) Test(level float64) CorrelationResult {
+	return fisherCorrelationResult(pc.Run(), pc.current.Size(), level, 1.0, 3)
+}
+
 func (pc *PearsonCorrelation) sanityCheck() error {
 	if pc.current.Size() != pc.target.Size() {
 		return errors.New("current and target series do not have the same dimension")
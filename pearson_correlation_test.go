@@ -24,3 +24,29 @@ func TestRunPearsonCorrelationWhenTimeSeriesHaveNoLinearRelation(t *testing.T) {
 		t.Fatalf("must return number close to 0")
 	}
 }
+
+func TestPearsonCorrelationSignificance(t *testing.T) {
+	timeSeriesA := NewTimeSeries([]float64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10})
+	timeSeriesB := NewTimeSeries([]float64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, []float64{3, 1, 4, 1, 5, 9, 2, 6, 5, 3})
+
+	result := NewPearsonCorrelation(timeSeriesA, timeSeriesB).Test(0.95)
+	if result.SampleSize != 10 || result.Level != 0.95 {
+		t.Fatalf("unexpected sample size %d or level %v", result.SampleSize, result.Level)
+	}
+	if math.Abs(result.PValue-0.345071) > 1e-5 {
+		t.Fatalf("expected a p-value of 0.345071, got %v", result.PValue)
+	}
+	if math.Abs(result.Lower+0.374035) > 1e-5 || math.Abs(result.Upper-0.796325) > 1e-5 {
+		t.Fatalf("expected interval [-0.374035, 0.796325], got [%v, %v]", result.Lower, result.Upper)
+	}
+}
+
+func TestPearsonCorrelationSignificanceWithTwoPoints(t *testing.T) {
+	timeSeriesA := NewTimeSeries([]float64{0, 1}, []float64{1, 2})
+	timeSeriesB := NewTimeSeries([]float64{0, 1}, []float64{3, 7})
+
+	result := NewCorrelator(timeSeriesA, timeSeriesB).CorrelationMethod(Pearson, nil).Test(0.95)
+	if result.PValue != 1 {
+		t.Fatalf("two points must not be significant, got p-value %v", result.PValue)
+	}
+}
@@ -0,0 +1,186 @@
+package anomalia
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// RootCauseAnalysis holds the root-cause ranking configuration.
+//
+// Given an anomaly on a KPI, it ranks candidate metrics by combining three pieces of evidence
+// gathered over the anomaly window plus some context: how much the candidate correlates with the KPI,
+// whether the candidate has an anomaly itself, and whether it moved before the KPI did.
+type RootCauseAnalysis struct {
+	kpi               *TimeSeries
+	anomaly           Anomaly
+	candidates        map[string]*TimeSeries
+	context           float64
+	method            CorrelationMethod
+	options           []float64
+	threshold         float64
+	correlationWeight float64
+	presenceWeight    float64
+	leadWeight        float64
+}
+
+// RootCauseEvidence holds the evidence gathered for a candidate metric and its resulting score.
+// The lead time is positive when the candidate moved before the KPI. It comes from the cross correlation
+// shift, which uses the whole shape of both series, or from the candidate anomaly with other correlation methods.
+type RootCauseEvidence struct {
+	Name             string
+	Rank             int
+	Score            float64
+	Correlation      float64
+	Shift            float64
+	HasAnomaly       bool
+	AnomalyTimestamp float64
+	AnomalyScore     float64
+	LeadTime         float64
+	HasLeadTime      bool
+	CorrelationScore float64
+	PresenceScore    float64
+	LeadScore        float64
+}
+
+// RootCauseReport holds the ranked candidates for the KPI anomaly.
+// Skipped lists the candidates which could not be analyzed (e.g. not enough data points in the window).
+type RootCauseReport struct {
+	Anomaly    Anomaly
+	Window     TimePeriod
+	Candidates []RootCauseEvidence
+	Skipped    []string
+}
+
+// NewRootCauseAnalysis returns an instance of the root-cause ranking for the anomaly of the KPI.
+func NewRootCauseAnalysis(kpi *TimeSeries, anomaly Anomaly, candidates map[string]*TimeSeries) *RootCauseAnalysis {
+	return &RootCauseAnalysis{
+		kpi:               kpi,
+		anomaly:           anomaly,
+		candidates:        candidates,
+		context:           30 * 60 * 1000,
+		method:            XCorr,
+		threshold:         2.0,
+		correlationWeight: 0.5,
+		presenceWeight:    0.3,
+		leadWeight:        0.2,
+	}
+}
+
+// Context sets how long (in seconds) before and after the anomaly window is analyzed (defaults to 30 minutes).
+func (rca *RootCauseAnalysis) Context(seconds float64) *RootCauseAnalysis {
+	rca.context = seconds * 1000
+	return rca
+}
+
+// CorrelationMethod specifies which correlation method to use (defaults to XCorr with a max shift of the context).
+func (rca *RootCauseAnalysis) CorrelationMethod(method CorrelationMethod, options []float64) *RootCauseAnalysis {
+	rca.method = method
+	rca.options = options
+	return rca
+}
+
+// Threshold sets the threshold used to detect anomalies in the candidates (defaults to 2.0).
+func (rca *RootCauseAnalysis) Threshold(threshold float64) *RootCauseAnalysis {
+	rca.threshold = threshold
+	return rca
+}
+
+// Weights sets how much the correlation, the anomaly presence and the lead time contribute to the score
+// (defaults to 0.5, 0.3 and 0.2).
+func (rca *RootCauseAnalysis) Weights(correlation, presence, lead float64) *RootCauseAnalysis {
+	rca.correlationWeight = correlation
+	rca.presenceWeight = presence
+	rca.leadWeight = lead
+	return rca
+}
+
+// Run ranks the candidates from the most to the least likely contributor.
+func (rca *RootCauseAnalysis) Run() (*RootCauseReport, error) {
+	start, end := anomalyWindow(rca.anomaly)
+	window := TimePeriod{start - rca.context, end + rca.context}
+	if rca.kpi.Crop(window.Start, window.End).Size() < 2 {
+		return nil, errors.New("not enough KPI data points in the anomaly window")
+	}
+
+	report := &RootCauseReport{Anomaly: rca.anomaly, Window: window}
+	for _, name := range sortedNames(rca.candidates) {
+		evidence, err := rca.gatherEvidence(name, window)
+		if err != nil {
+			report.Skipped = append(report.Skipped, name)
+			continue
+		}
+		report.Candidates = append(report.Candidates, evidence)
+	}
+
+	sort.SliceStable(report.Candidates, func(i, j int) bool {
+		return report.Candidates[i].Score > report.Candidates[j].Score
+	})
+	for idx := range report.Candidates {
+		report.Candidates[idx].Rank = idx + 1
+	}
+	return report, nil
+}
+
+func (rca *RootCauseAnalysis) gatherEvidence(name string, window TimePeriod) (RootCauseEvidence, error) {
+	evidence := RootCauseEvidence{Name: name}
+	candidate := rca.candidates[name]
+
+	options := rca.options
+	if rca.method == XCorr && options == nil {
+		options = []float64{rca.context / 1000, 0.05}
+	}
+	current, target := alignedCopies(rca.kpi, candidate, rca.method)
+	result, err := NewCorrelator(current, target).
+		TimePeriod(window.Start, window.End).
+		CorrelationMethod(rca.method, options).
+		result()
+	if err != nil {
+		return evidence, err
+	}
+	evidence.Correlation = result.Coefficient
+	evidence.Shift = result.Shift
+	evidence.CorrelationScore = math.Min(math.Abs(result.Coefficient), 1)
+
+	// A positive shift means the candidate lags behind the KPI
+	if rca.method == XCorr {
+		evidence.LeadTime, evidence.HasLeadTime = -result.Shift, true
+	}
+
+	cropped := candidate.Crop(window.Start, window.End)
+	if cropped.Size() >= 2 {
+		detector := NewDetector(cropped).Threshold(rca.threshold)
+		for _, anomaly := range detector.GetAnomalies(detector.GetScores()) {
+			if !evidence.HasAnomaly || math.Abs(anomaly.Timestamp-rca.anomaly.Timestamp) < math.Abs(evidence.AnomalyTimestamp-rca.anomaly.Timestamp) {
+				evidence.HasAnomaly = true
+				evidence.AnomalyTimestamp = anomaly.Timestamp
+				evidence.AnomalyScore = anomaly.Score
+			}
+		}
+	}
+	if evidence.HasAnomaly {
+		evidence.PresenceScore = 1
+		if !evidence.HasLeadTime {
+			evidence.LeadTime, evidence.HasLeadTime = rca.anomaly.Timestamp-evidence.AnomalyTimestamp, true
+		}
+	}
+
+	// Leading candidates score above 0.5, lagging ones below
+	if evidence.HasLeadTime {
+		evidence.LeadScore = 0.5 * (1 + math.Tanh(2*evidence.LeadTime/rca.context))
+	}
+
+	evidence.Score = rca.correlationWeight*evidence.CorrelationScore +
+		rca.presenceWeight*evidence.PresenceScore +
+		rca.leadWeight*evidence.LeadScore
+	return evidence, nil
+}
+
+func sortedNames(series map[string]*TimeSeries) []string {
+	names := make([]string, 0, len(series))
+	for name := range series {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,86 @@
+package anomalia
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// generateIncidentTimeSeries generates a baseline at one minute resolution with a bump peaking at the given minute.
+func generateIncidentTimeSeries(rng *rand.Rand, size int, peak float64, height float64) *TimeSeries {
+	timestamps, values := make([]float64, size), make([]float64, size)
+	for i := range values {
+		timestamps[i] = float64(i) * 60 * 1000
+		values[i] = 10 + 0.2*rng.NormFloat64() + height*math.Exp(-(float64(i)-peak)*(float64(i)-peak)/8)
+	}
+	return NewTimeSeries(timestamps, values)
+}
+
+func TestRootCauseAnalysis(t *testing.T) {
+	rng := rand.New(rand.NewSource(12))
+	kpi := generateIncidentTimeSeries(rng, 180, 90, 10)
+	candidates := map[string]*TimeSeries{
+		"database": generateIncidentTimeSeries(rng, 180, 87, 10),
+		"cache":    generateIncidentTimeSeries(rng, 180, 95, 10),
+		"disk":     generateIncidentTimeSeries(rng, 180, 90, 0),
+		"sparse":   NewTimeSeries([]float64{0}, []float64{1}),
+	}
+	anomaly := Anomaly{Timestamp: 90 * 60 * 1000, StartTimestamp: 88 * 60 * 1000, EndTimestamp: 92 * 60 * 1000}
+
+	report, err := NewRootCauseAnalysis(kpi, anomaly, candidates).Context(30 * 60).Run()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(report.Candidates) != 3 || len(report.Skipped) != 1 || report.Skipped[0] != "sparse" {
+		t.Fatalf("expected 3 ranked candidates and sparse to be skipped, got %+v", report)
+	}
+
+	top := report.Candidates[0]
+	if top.Name != "database" || top.Rank != 1 || top.LeadTime <= 0 {
+		t.Fatalf("expected the leading database to rank first, got %+v", top)
+	}
+	if last := report.Candidates[2]; last.Name != "disk" {
+		t.Fatalf("expected the flat disk to rank last, got %+v", last)
+	}
+
+	for _, evidence := range report.Candidates {
+		if evidence.Name == "cache" && evidence.LeadTime >= 0 {
+			t.Fatalf("expected the cache to lag behind the KPI, got %+v", evidence)
+		}
+	}
+}
+
+func TestRootCauseAnalysisWithoutKPIData(t *testing.T) {
+	kpi := NewTimeSeries([]float64{0, 1000}, []float64{1, 2})
+	if _, err := NewRootCauseAnalysis(kpi, Anomaly{Timestamp: 1e9}, nil).Context(1).Run(); err == nil {
+		t.Fatalf("must fail when the KPI has no data points in the window")
+	}
+}
+
+func TestRootCauseAnalysisWithDifferentSamplingGrids(t *testing.T) {
+	rng := rand.New(rand.NewSource(12))
+	kpi := generateIncidentTimeSeries(rng, 180, 90, 10)
+	database := generateIncidentTimeSeries(rng, 180, 90, 10)
+	for i := range database.Timestamps {
+		// Sampled 30 seconds after the KPI
+		database.Timestamps[i] += 30 * 1000
+	}
+	anomaly := Anomaly{Timestamp: 90 * 60 * 1000, StartTimestamp: 88 * 60 * 1000, EndTimestamp: 92 * 60 * 1000}
+
+	for _, method := range []CorrelationMethod{Pearson, SpearmanRank, KendallTau, KSGMutualInformation, DCor} {
+		report, err := NewRootCauseAnalysis(kpi, anomaly, map[string]*TimeSeries{"database": database}).
+			Context(30 * 60).
+			CorrelationMethod(method, nil).
+			Run()
+		if err != nil {
+			t.Fatalf("method %d: unexpected error: %v", method, err)
+		}
+		if len(report.Candidates) != 1 || len(report.Skipped) != 0 {
+			t.Fatalf("method %d: expected the candidate to be ranked, got %+v", method, report)
+		}
+		if report.Candidates[0].CorrelationScore < 0.25 {
+			t.Fatalf("method %d: expected a positive dependency, got %+v", method, report.Candidates[0])
+		}
+	}
+}
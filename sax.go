@@ -0,0 +1,133 @@
+package anomalia
+
+import (
+	"errors"
+	"math"
+	"strings"
+)
+
+const (
+	minAlphabetSize = 3
+	maxAlphabetSize = 20
+)
+
+// SAX holds the Symbolic Aggregate approXimation configuration.
+//
+// The values are z-normalized, reduced with the Piecewise Aggregate Approximation (PAA)
+// and discretized using breakpoints that split the standard normal distribution into
+// equiprobable regions. Each region is represented by a letter starting from 'a'.
+// The paper describing this representation can be found here: https://doi.org/10.1145/882082.882086
+type SAX struct {
+	segments     int
+	alphabetSize int
+}
+
+// NewSAX returns SAX instance.
+func NewSAX() *SAX {
+	return &SAX{segments: 8, alphabetSize: 4}
+}
+
+// Segments sets the number of PAA segments (defaults to 8).
+func (s *SAX) Segments(n int) *SAX {
+	s.segments = n
+	return s
+}
+
+// AlphabetSize sets the number of symbols, between 3 and 20 (defaults to 4).
+func (s *SAX) AlphabetSize(size int) *SAX {
+	s.alphabetSize = size
+	return s
+}
+
+// Transform returns the SAX word of the values.
+func (s *SAX) Transform(values []float64) (BitmapBinary, error) {
+	if err := s.sanityCheck(values); err != nil {
+		return "", err
+	}
+	return discretize(PAA(ZNormalize(values), s.segments), s.alphabetSize), nil
+}
+
+// MinDist returns the lower bounding distance between two SAX words of the same
+// length that were generated from sequences of n values.
+func (s *SAX) MinDist(a, b BitmapBinary, n int) float64 {
+	if a.Len() != b.Len() {
+		panic("SAX words must have the same length")
+	}
+	breakpoints := gaussianBreakpoints(s.alphabetSize)
+
+	sum := 0.0
+	for i := 0; i < len(a); i++ {
+		r, c := int(a[i]-'a'), int(b[i]-'a')
+		if AbsInt(r-c) > 1 {
+			high, low := maxInt(r, c), minInt(r, c)
+			d := breakpoints[high-1] - breakpoints[low]
+			sum += d * d
+		}
+	}
+	return math.Sqrt(float64(n)/float64(a.Len())) * math.Sqrt(sum)
+}
+
+func (s *SAX) sanityCheck(values []float64) error {
+	if s.alphabetSize < minAlphabetSize || s.alphabetSize > maxAlphabetSize {
+		return errors.New("alphabet size must be between 3 and 20")
+	}
+	if s.segments < 1 || s.segments > len(values) {
+		return errors.New("invalid number of segments")
+	}
+	return nil
+}
+
+// ZNormalize returns the values with zero mean and unit standard deviation.
+// Values with (almost) no variation are only centered to avoid amplifying noise.
+func ZNormalize(values []float64) []float64 {
+	mean, stdev := Average(values), Stdev(values)
+	return mapSlice(values, func(value float64) float64 {
+		if stdev < 1e-8 {
+			return value - mean
+		}
+		return (value - mean) / stdev
+	})
+}
+
+// PAA returns the Piecewise Aggregate Approximation of the values using the specified number of segments.
+// The values do not need to be divisible by the number of segments: boundary values contribute
+// proportionally to the adjacent segments.
+func PAA(values []float64, segments int) []float64 {
+	n := len(values)
+	if segments == n {
+		return copySlice(values)
+	}
+
+	paa := make([]float64, segments)
+	for i := 0; i < n*segments; i++ {
+		paa[i/n] += values[i/segments]
+	}
+	for i := range paa {
+		paa[i] /= float64(n)
+	}
+	return paa
+}
+
+// gaussianBreakpoints returns the breakpoints splitting the standard normal distribution into equiprobable regions.
+func gaussianBreakpoints(alphabetSize int) []float64 {
+	quantile := Quantile(0.0, 1.0)
+	breakpoints := make([]float64, alphabetSize-1)
+	for i := range breakpoints {
+		breakpoints[i] = quantile(float64(i+1) / float64(alphabetSize))
+	}
+	return breakpoints
+}
+
+func discretize(values []float64, alphabetSize int) BitmapBinary {
+	breakpoints := gaussianBreakpoints(alphabetSize)
+
+	var builder strings.Builder
+	for _, value := range values {
+		symbol := 0
+		for symbol < len(breakpoints) && value >= breakpoints[symbol] {
+			symbol++
+		}
+		builder.WriteByte(byte('a' + symbol))
+	}
+	return BitmapBinary(builder.String())
+}
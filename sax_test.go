@@ -0,0 +1,60 @@
+package anomalia
+
+import (
+	"math"
+	"testing"
+)
+
+func TestZNormalize(t *testing.T) {
+	normalized := ZNormalize([]float64{2, 4, 4, 4, 5, 5, 7, 9})
+	if math.Abs(Average(normalized)) > 1e-9 || math.Abs(Stdev(normalized)-1) > 1e-9 {
+		t.Fatalf("z-normalized values must have zero mean and unit standard deviation")
+	}
+}
+
+func TestPAA(t *testing.T) {
+	actual := PAA([]float64{1, 2, 3, 4, 5, 6}, 3)
+	expected := []float64{1.5, 3.5, 5.5}
+	for i := range expected {
+		if math.Abs(actual[i]-expected[i]) > 1e-9 {
+			t.Fatalf("expected %v, got %v", expected, actual)
+		}
+	}
+
+	if uneven := PAA([]float64{1, 2, 3, 4, 5}, 2); math.Abs(uneven[0]-1.8) > 1e-9 || math.Abs(uneven[1]-4.2) > 1e-9 {
+		t.Fatalf("boundary values must be split between segments, got %v", uneven)
+	}
+}
+
+func TestGaussianBreakpoints(t *testing.T) {
+	breakpoints := gaussianBreakpoints(4)
+	expected := []float64{-0.6745, 0, 0.6745}
+	for i := range expected {
+		if math.Abs(breakpoints[i]-expected[i]) > 1e-4 {
+			t.Fatalf("expected %v, got %v", expected, breakpoints)
+		}
+	}
+}
+
+func TestSAXTransformAndMinDist(t *testing.T) {
+	sax := NewSAX().Segments(4).AlphabetSize(4)
+	a, err := sax.Transform([]float64{-2, -2, -1, -1, 1, 1, 2, 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if a != "abcd" {
+		t.Fatalf("expected 'abcd', got '%s'", a)
+	}
+
+	b, _ := sax.Transform([]float64{2, 2, 1, 1, -1, -1, -2, -2})
+	if dist := sax.MinDist(a, a, 8); dist != 0 {
+		t.Fatalf("distance of a word to itself must be 0")
+	}
+	if dist := sax.MinDist(a, b, 8); dist <= 0 {
+		t.Fatalf("distance between opposite words must be positive")
+	}
+
+	if _, err := NewSAX().AlphabetSize(21).Transform(make([]float64, 10)); err == nil {
+		t.Fatalf("alphabet size must be validated")
+	}
+}
@@ -1,5 +1,10 @@
 package anomalia
 
+import (
+	"math"
+	"sort"
+)
+
 const noisePercentageThreshold = 0.001
 
 // ScoreList holds timestamps and their scores
@@ -8,6 +13,11 @@ type ScoreList struct {
 	Scores     []float64
 }
 
+// NewScoreListFromTimeSeries creates a score list using the time series values as scores.
+func NewScoreListFromTimeSeries(ts *TimeSeries) *ScoreList {
+	return &ScoreList{copySlice(ts.Timestamps), copySlice(ts.Values)}
+}
+
 // Denoise sets low(noisy) scores to 0.0
 func (sl *ScoreList) Denoise() *ScoreList {
 	threshold := noisePercentageThreshold * sl.Max()
@@ -27,6 +37,17 @@ func (sl *ScoreList) Max() float64 {
 	return max
 }
 
+// Min returns the minimum of the scores
+func (sl *ScoreList) Min() float64 {
+	min, _ := minMax(sl.Scores)
+	return min
+}
+
+// Size returns the score list dimension/size.
+func (sl *ScoreList) Size() int {
+	return len(sl.Timestamps)
+}
+
 // Zip convert the score list to map (map[Timestamp]Score)
 func (sl *ScoreList) Zip() map[float64]float64 {
 	m := make(map[float64]float64)
@@ -37,3 +58,171 @@ func (sl *ScoreList) Zip() map[float64]float64 {
 	}
 	return m
 }
+
+// ToTimeSeries converts the score list to a time series using the scores as values.
+func (sl *ScoreList) ToTimeSeries() *TimeSeries {
+	return NewTimeSeries(copySlice(sl.Timestamps), copySlice(sl.Scores))
+}
+
+// NormalizeMinMax rescales the scores into the [0, 1] range.
+// Scores are all set to 0 when they are all equal.
+func (sl *ScoreList) NormalizeMinMax() *ScoreList {
+	min, max := minMax(sl.Scores)
+	normalized := mapSlice(sl.Scores, func(score float64) float64 {
+		if max == min {
+			return 0.0
+		}
+		return (score - min) / (max - min)
+	})
+	return &ScoreList{sl.Timestamps, normalized}
+}
+
+// NormalizeZScore standardizes the scores to have zero mean and unit standard deviation.
+func (sl *ScoreList) NormalizeZScore() *ScoreList {
+	mean, stdev := Average(sl.Scores), Stdev(sl.Scores)
+	normalized := mapSlice(sl.Scores, func(score float64) float64 {
+		if stdev == 0 {
+			return 0.0
+		}
+		return (score - mean) / stdev
+	})
+	return &ScoreList{sl.Timestamps, normalized}
+}
+
+// NormalizeRank replaces each score by its percentile rank in the [0, 1] range.
+// Tied scores share the same (average) rank.
+func (sl *ScoreList) NormalizeRank() *ScoreList {
+	n := len(sl.Scores)
+	normalized := make([]float64, n)
+	if n == 1 {
+		return &ScoreList{sl.Timestamps, normalized}
+	}
+
+	ranks := averageRanks(sl.Scores)
+	for i, r := range ranks {
+		normalized[i] = (r - 1) / float64(n-1)
+	}
+	return &ScoreList{sl.Timestamps, normalized}
+}
+
+// Add adds the scores of both score lists element-wise.
+// Timestamps missing from one of the lists are considered to have a score of 0.
+func (sl *ScoreList) Add(other *ScoreList) *ScoreList {
+	return sl.combine(other, func(a, b float64) float64 { return a + b })
+}
+
+// Maximum keeps the maximal score of both score lists element-wise.
+// Timestamps missing from one of the lists are considered to have a score of 0.
+func (sl *ScoreList) Maximum(other *ScoreList) *ScoreList {
+	return sl.combine(other, math.Max)
+}
+
+// Weighted combines both score lists element-wise using the weight for the current
+// scores and (1 - weight) for the other scores.
+// Timestamps missing from one of the lists are considered to have a score of 0.
+func (sl *ScoreList) Weighted(other *ScoreList, weight float64) *ScoreList {
+	return sl.combine(other, func(a, b float64) float64 { return weight*a + (1-weight)*b })
+}
+
+// Crop crops the score list timestamps into the specified range [start, end]
+func (sl *ScoreList) Crop(start, end float64) *ScoreList {
+	timestamps := make([]float64, 0)
+	scores := make([]float64, 0)
+	for idx, timestamp := range sl.Timestamps {
+		if (timestamp >= start) && (timestamp <= end) {
+			timestamps = append(timestamps, timestamp)
+			scores = append(scores, sl.Scores[idx])
+		}
+	}
+	return &ScoreList{timestamps, scores}
+}
+
+// TopK returns the k highest scores ordered by timestamp.
+func (sl *ScoreList) TopK(k int) *ScoreList {
+	indices := make([]int, len(sl.Scores))
+	for i := range indices {
+		indices[i] = i
+	}
+	sort.SliceStable(indices, func(i, j int) bool { return sl.Scores[indices[i]] > sl.Scores[indices[j]] })
+
+	if k > len(indices) {
+		k = len(indices)
+	} else if k < 0 {
+		k = 0
+	}
+	indices = indices[:k]
+	sort.Ints(indices)
+
+	timestamps := make([]float64, k)
+	scores := make([]float64, k)
+	for i, idx := range indices {
+		timestamps[i] = sl.Timestamps[idx]
+		scores[i] = sl.Scores[idx]
+	}
+	return &ScoreList{timestamps, scores}
+}
+
+// Threshold converts the score list to a binary mask where scores above
+// the threshold are set to 1 and all others to 0.
+func (sl *ScoreList) Threshold(threshold float64) *ScoreList {
+	mask := mapSlice(sl.Scores, func(score float64) float64 {
+		if score > threshold {
+			return 1.0
+		}
+		return 0.0
+	})
+	return &ScoreList{sl.Timestamps, mask}
+}
+
+// combine applies fn to the scores of both lists which share the same timestamp.
+// The timestamps do not need to be sorted, and when a timestamp appears more than once
+// in a list, its last score is used.
+func (sl *ScoreList) combine(other *ScoreList, fn func(float64, float64) float64) *ScoreList {
+	scores, otherScores := sl.scoresByTimestamp(), other.scoresByTimestamp()
+
+	union := make(map[float64]float64, len(scores)+len(otherScores))
+	for timestamp, score := range scores {
+		union[timestamp] = fn(score, otherScores[timestamp])
+	}
+	for timestamp, otherScore := range otherScores {
+		if _, ok := scores[timestamp]; !ok {
+			union[timestamp] = fn(0.0, otherScore)
+		}
+	}
+
+	timestamps, combined := unpackMap(union)
+	return &ScoreList{timestamps, combined}
+}
+
+// scoresByTimestamp maps each timestamp to its own score, keeping the original pairs.
+func (sl *ScoreList) scoresByTimestamp() map[float64]float64 {
+	m := make(map[float64]float64, len(sl.Timestamps))
+	for idx, timestamp := range sl.Timestamps {
+		m[timestamp] = sl.Scores[idx]
+	}
+	return m
+}
+
+// averageRanks returns 1-based ranks of the input where ties get the average rank.
+func averageRanks(input []float64) []float64 {
+	n := len(input)
+	indices := make([]int, n)
+	for i := range indices {
+		indices[i] = i
+	}
+	sort.SliceStable(indices, func(i, j int) bool { return input[indices[i]] < input[indices[j]] })
+
+	ranks := make([]float64, n)
+	for i := 0; i < n; {
+		j := i
+		for j+1 < n && input[indices[j+1]] == input[indices[i]] {
+			j++
+		}
+		avg := float64(i+j)/2 + 1
+		for k := i; k <= j; k++ {
+			ranks[indices[k]] = avg
+		}
+		i = j + 1
+	}
+	return ranks
+}
@@ -1,6 +1,10 @@
 package anomalia
 
-import "testing"
+import (
+	"math"
+	"reflect"
+	"testing"
+)
 
 func TestDenoiseScoreList(t *testing.T) {
 	denoised := fakeScoreList().Denoise()
@@ -27,3 +31,90 @@ func fakeScoreList() *ScoreList {
 		Scores:     []float64{0.0010, 4.6, 4.6, 4.6, 1.0, 1.0},
 	}
 }
+
+func TestNormalizeScoreList(t *testing.T) {
+	minMax := fakeScoreList().NormalizeMinMax()
+	if minMax.Min() != 0.0 || minMax.Max() != 1.0 {
+		t.Fatalf("min-max normalized scores must be within [0, 1]")
+	}
+
+	zScore := fakeScoreList().NormalizeZScore()
+	if mean := Average(zScore.Scores); math.Abs(mean) > 1e-9 {
+		t.Fatalf("z-score normalized scores must have zero mean, got %v", mean)
+	}
+
+	rank := fakeScoreList().NormalizeRank()
+	expected := []float64{0, 0.8, 0.8, 0.8, 0.3, 0.3}
+	for i, score := range rank.Scores {
+		if math.Abs(score-expected[i]) > 1e-9 {
+			t.Fatalf("expected %v, got %v", expected, rank.Scores)
+		}
+	}
+}
+
+func TestEmptyScoreList(t *testing.T) {
+	empty := &ScoreList{Timestamps: []float64{}, Scores: []float64{}}
+	if empty.Min() != 0 || empty.Max() != 0 {
+		t.Fatalf("min and max of an empty score list must be 0")
+	}
+	if normalized := empty.NormalizeMinMax(); normalized.Size() != 0 {
+		t.Fatalf("normalizing an empty score list must return an empty score list")
+	}
+}
+
+func TestCombineScoreLists(t *testing.T) {
+	a := &ScoreList{Timestamps: []float64{1, 2, 3}, Scores: []float64{1, 2, 3}}
+	b := &ScoreList{Timestamps: []float64{2, 3, 4}, Scores: []float64{3, 1, 5}}
+
+	sum := a.Add(b)
+	if !reflect.DeepEqual(sum.Timestamps, []float64{1, 2, 3, 4}) {
+		t.Fatalf("combined timestamps must be the union of both lists, got %v", sum.Timestamps)
+	}
+	if !reflect.DeepEqual(sum.Scores, []float64{1, 5, 4, 5}) {
+		t.Fatalf("unexpected sum of scores: %v", sum.Scores)
+	}
+
+	if max := a.Maximum(b); !reflect.DeepEqual(max.Scores, []float64{1, 3, 3, 5}) {
+		t.Fatalf("unexpected maximum of scores: %v", max.Scores)
+	}
+
+	if weighted := a.Weighted(b, 0.5); !reflect.DeepEqual(weighted.Scores, []float64{0.5, 2.5, 2, 2.5}) {
+		t.Fatalf("unexpected weighted scores: %v", weighted.Scores)
+	}
+}
+
+func TestCombineUnsortedScoreLists(t *testing.T) {
+	unsorted := &ScoreList{Timestamps: []float64{2, 1}, Scores: []float64{20, 10}}
+	zeros := &ScoreList{Timestamps: []float64{1, 2}, Scores: []float64{0, 0}}
+
+	sum := unsorted.Add(zeros)
+	if !reflect.DeepEqual(sum.Timestamps, []float64{1, 2}) || !reflect.DeepEqual(sum.Scores, []float64{10, 20}) {
+		t.Fatalf("scores must stay paired with their timestamps, got %v %v", sum.Timestamps, sum.Scores)
+	}
+
+	duplicated := &ScoreList{Timestamps: []float64{1, 2, 1}, Scores: []float64{1, 2, 3}}
+	if sum := duplicated.Add(zeros); !reflect.DeepEqual(sum.Scores, []float64{3, 2}) {
+		t.Fatalf("the last score of a duplicated timestamp must be used, got %v", sum.Scores)
+	}
+}
+
+func TestScoreListSelection(t *testing.T) {
+	sl := fakeScoreList()
+
+	if cropped := sl.Crop(2, 4); !reflect.DeepEqual(cropped.Timestamps, []float64{2, 3, 4}) {
+		t.Fatalf("unexpected cropped timestamps: %v", cropped.Timestamps)
+	}
+
+	if top := sl.TopK(2); !reflect.DeepEqual(top.Timestamps, []float64{2, 3}) {
+		t.Fatalf("unexpected top-k timestamps: %v", top.Timestamps)
+	}
+
+	if mask := sl.Threshold(2); !reflect.DeepEqual(mask.Scores, []float64{0, 1, 1, 1, 0, 0}) {
+		t.Fatalf("unexpected binary mask: %v", mask.Scores)
+	}
+
+	ts := sl.ToTimeSeries()
+	if !reflect.DeepEqual(NewScoreListFromTimeSeries(ts), sl) {
+		t.Fatalf("score list must survive a round trip through time series")
+	}
+}
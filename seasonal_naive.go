@@ -0,0 +1,72 @@
+package anomalia
+
+import (
+	"errors"
+	"math"
+)
+
+// SeasonalNaive holds the seasonal naive forecasting method configuration.
+// Each point is forecasted as the last observed value from the same season.
+type SeasonalNaive struct {
+	period     int
+	lastSeason []float64
+	forecastState
+}
+
+// NewSeasonalNaive returns SeasonalNaive instance for the specified seasonal period.
+// A period of 1 gives the naive (random walk) method.
+func NewSeasonalNaive(period int) *SeasonalNaive {
+	return &SeasonalNaive{period: period}
+}
+
+// Fit fits the model on the time series.
+func (sn *SeasonalNaive) Fit(timeSeries *TimeSeries) error {
+	values, m := timeSeries.Values, sn.period
+	if m < 1 {
+		return errors.New("period must be positive")
+	}
+	if len(values) <= m {
+		return errors.New("not enough data points")
+	}
+
+	fitted := make([]float64, len(values))
+	for i := range fitted {
+		if i < m {
+			fitted[i] = math.NaN()
+		} else {
+			fitted[i] = values[i-m]
+		}
+	}
+	sn.lastSeason = copySlice(values[len(values)-m:])
+	sn.fit(timeSeries, fitted)
+	return nil
+}
+
+// Predict returns the forecast of the next horizon points.
+func (sn *SeasonalNaive) Predict(horizon int) (*TimeSeries, error) {
+	if err := checkHorizon(horizon); err != nil {
+		return nil, err
+	}
+	return sn.predict(sn.points(horizon))
+}
+
+// PredictInterval returns the forecast of the next horizon points with their prediction interval.
+func (sn *SeasonalNaive) PredictInterval(horizon int, level float64) (*Forecast, error) {
+	if err := checkHorizon(horizon); err != nil {
+		return nil, err
+	}
+	return sn.predictInterval(sn.points(horizon), level, func(h int) float64 {
+		return float64((h-1)/sn.period + 1)
+	})
+}
+
+func (sn *SeasonalNaive) points(horizon int) []float64 {
+	points := make([]float64, horizon)
+	if !sn.isFitted {
+		return points
+	}
+	for i := range points {
+		points[i] = sn.lastSeason[i%sn.period]
+	}
+	return points
+}
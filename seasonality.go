@@ -0,0 +1,123 @@
+package anomalia
+
+import (
+	"errors"
+	"math"
+	"math/cmplx"
+	"sort"
+)
+
+const (
+	maxPeriodCandidates      = 3
+	minSeasonalityConfidence = 0.3
+)
+
+// PeriodCandidate holds a detected seasonal period and the confidence in it.
+// The confidence is the autocorrelation of the time series at the period lag.
+type PeriodCandidate struct {
+	Period     int
+	Confidence float64
+}
+
+// DetectPeriod returns candidate seasonal periods (in data points) of the time series ordered by decreasing confidence.
+//
+// The time series is first detrended, then the strongest frequencies of the FFT periodogram
+// are validated against the peaks of the autocorrelation function (AUTOPERIOD method).
+// Only periods whose autocorrelation is significant are returned, so an empty result means no seasonality was found.
+// The paper describing this method can be found here: https://doi.org/10.1137/1.9781611972757.40
+func DetectPeriod(timeSeries *TimeSeries) ([]PeriodCandidate, error) {
+	n := timeSeries.Size()
+	if n < 8 {
+		return nil, errors.New("not enough data points")
+	}
+
+	values := detrend(timeSeries.Values)
+	acf := autocorrelation(values, n/2+1)
+
+	// Find the strongest frequencies of the periodogram
+	spectrum := realFFT(values)
+	power := make([]float64, n/2+1)
+	for k := 1; k <= n/2; k++ {
+		power[k] = cmplx.Abs(spectrum[k]) * cmplx.Abs(spectrum[k])
+	}
+	frequencies := make([]int, 0)
+	for k := 2; k <= n/2; k++ {
+		if isLocalPeak(power, k) {
+			frequencies = append(frequencies, k)
+		}
+	}
+	sort.SliceStable(frequencies, func(i, j int) bool { return power[frequencies[i]] > power[frequencies[j]] })
+	if len(frequencies) > maxPeriodCandidates {
+		frequencies = frequencies[:maxPeriodCandidates]
+	}
+
+	// Validate each frequency against the autocorrelation function, whose peaks give accurate periods
+	significance := 1.96 / math.Sqrt(float64(n))
+	seen := make(map[int]bool)
+	candidates := make([]PeriodCandidate, 0, len(frequencies))
+	for _, k := range frequencies {
+		lower := maxInt(int(math.Floor(float64(n)/float64(k+1))), 2)
+		upper := minInt(int(math.Ceil(float64(n)/float64(k-1))), n/2)
+
+		best := -1
+		for lag := lower; lag <= upper; lag++ {
+			if isLocalPeak(acf, lag) && (best == -1 || acf[lag] > acf[best]) {
+				best = lag
+			}
+		}
+		if best != -1 && !seen[best] && acf[best] > significance {
+			seen[best] = true
+			candidates = append(candidates, PeriodCandidate{Period: best, Confidence: acf[best]})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Confidence > candidates[j].Confidence })
+	return candidates, nil
+}
+
+// autocorrelation returns the autocorrelation function of the values for lags in [0, maxLag).
+func autocorrelation(values []float64, maxLag int) []float64 {
+	n := len(values)
+	mean := Average(values)
+	centered := mapSlice(values, func(value float64) float64 { return value - mean })
+	reversed := make([]float64, n)
+	for i, value := range centered {
+		reversed[n-1-i] = value
+	}
+	products := convolve(centered, reversed)
+
+	acf := make([]float64, minInt(maxLag, n))
+	for lag := range acf {
+		if products[n-1] != 0 {
+			acf[lag] = products[n-1+lag] / products[n-1]
+		}
+	}
+	return acf
+}
+
+// detrend removes the least squares linear trend from the values.
+func detrend(values []float64) []float64 {
+	n := float64(len(values))
+	meanX, meanY := (n-1)/2, Average(values)
+
+	covariance, variance := 0.0, 0.0
+	for i, value := range values {
+		covariance += (float64(i) - meanX) * (value - meanY)
+		variance += (float64(i) - meanX) * (float64(i) - meanX)
+	}
+
+	slope := 0.0
+	if variance != 0 {
+		slope = covariance / variance
+	}
+	return mapSliceWithIndex(values, func(i int, value float64) float64 {
+		return value - meanY - slope*(float64(i)-meanX)
+	})
+}
+
+func isLocalPeak(data []float64, idx int) bool {
+	if idx <= 0 || idx >= len(data)-1 {
+		return false
+	}
+	return data[idx] > data[idx-1] && data[idx] >= data[idx+1]
+}
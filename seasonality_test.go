@@ -0,0 +1,35 @@
+package anomalia
+
+import "testing"
+
+func TestDetectPeriod(t *testing.T) {
+	candidates, err := DetectPeriod(NewTimeSeriesFromCSV("testdata/airline-passengers.csv"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(candidates) == 0 || candidates[0].Period != 12 {
+		t.Fatalf("expected a yearly period of 12 months, got %v", candidates)
+	}
+
+	if candidates, _ = DetectPeriod(generatePeriodicTimeSeries(500, 24)); candidates[0].Period != 24 {
+		t.Fatalf("expected a period of 24, got %v", candidates)
+	}
+}
+
+func TestDetectPeriodWithoutSeasonality(t *testing.T) {
+	candidates, err := DetectPeriod(generateFakeTimeSeries(500))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, candidate := range candidates {
+		if candidate.Confidence >= minSeasonalityConfidence {
+			t.Fatalf("random values must not have a confident period, got %v", candidates)
+		}
+	}
+
+	if _, err := DetectPeriod(NewTimeSeries([]float64{1, 2}, []float64{1, 2})); err == nil {
+		t.Fatalf("must fail when not enough data points")
+	}
+}
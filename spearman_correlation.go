@@ -89,6 +89,12 @@ func (sc *SpearmanCorrelation) Run() float64 {
 	return NewPearsonCorrelation(sc.current, sc.target).Run()
 }
 
+// Test runs the spearman correlation along with its significance.
+// The confidence interval uses the Fisher z-transformation with the Fieller, Hartley and Pearson variance.
+func (sc *SpearmanCorrelation) Test(level float64) CorrelationResult {
+	return fisherCorrelationResult(sc.Run(), sc.current.Size(), level, 1.06, 3)
+}
+
 func (sc *SpearmanCorrelation) sanityCheck() error {
 	if sc.current.Size() < 3 || sc.current.Size() != sc.target.Size() {
 		return errors.New("current and/or target series have an invalid dimension")
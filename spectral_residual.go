@@ -0,0 +1,155 @@
+package anomalia
+
+import (
+	"errors"
+	"math"
+	"math/cmplx"
+)
+
+// SpectralResidual holds the Spectral Residual algorithm configuration.
+//
+// The algorithm computes the saliency map of the time series: the log amplitude spectrum minus its
+// local average (the spectral residual) is transformed back to the time domain using the original phase.
+// Points standing out in the saliency map are scored by their relative deviation from the local average of the map.
+// To score the newest points reliably, the time series is extrapolated with a few estimated points before the transform.
+// The paper describing this algorithm can be found here: https://arxiv.org/abs/1906.03821
+type SpectralResidual struct {
+	amplitudeWindowSize int
+	scoreWindowSize     int
+	extrapolatedPoints  int
+	lookBackPoints      int
+}
+
+// NewSpectralResidual returns SpectralResidual instance.
+func NewSpectralResidual() *SpectralResidual {
+	return &SpectralResidual{
+		amplitudeWindowSize: 3,
+		scoreWindowSize:     21,
+		extrapolatedPoints:  5,
+		lookBackPoints:      5,
+	}
+}
+
+// AmplitudeWindowSize sets the size of the window averaging the log amplitude spectrum (defaults to 3).
+func (sr *SpectralResidual) AmplitudeWindowSize(size int) *SpectralResidual {
+	sr.amplitudeWindowSize = size
+	return sr
+}
+
+// ScoreWindowSize sets the size of the lagging window averaging the saliency map (defaults to 21).
+func (sr *SpectralResidual) ScoreWindowSize(size int) *SpectralResidual {
+	sr.scoreWindowSize = size
+	return sr
+}
+
+// ExtrapolatedPoints sets the number of points appended to the time series before the transform (defaults to 5).
+func (sr *SpectralResidual) ExtrapolatedPoints(n int) *SpectralResidual {
+	sr.extrapolatedPoints = n
+	return sr
+}
+
+// LookBackPoints sets the number of preceding points used to estimate the extrapolation gradient (defaults to 5).
+func (sr *SpectralResidual) LookBackPoints(n int) *SpectralResidual {
+	sr.lookBackPoints = n
+	return sr
+}
+
+// Run runs the spectral residual algorithm over the time series.
+func (sr *SpectralResidual) Run(timeSeries *TimeSeries) *ScoreList {
+	scoreList, _ := sr.computeScores(timeSeries)
+	return scoreList
+}
+
+func (sr *SpectralResidual) computeScores(timeSeries *TimeSeries) (*ScoreList, error) {
+	if err := sr.sanityCheck(timeSeries); err != nil {
+		return nil, err
+	}
+
+	n := timeSeries.Size()
+	values := sr.extrapolate(timeSeries.Values)
+	saliency := sr.saliencyMap(values)[:n]
+
+	scores := mapSliceWithIndex(saliency, func(idx int, value float64) float64 {
+		window := saliency[maxInt(idx-sr.scoreWindowSize, 0):idx]
+		if len(window) == 0 {
+			return 0.0
+		}
+		average := Average(window)
+		if average == 0 {
+			return 0.0
+		}
+		return math.Abs(value-average) / average
+	})
+	return &ScoreList{timeSeries.Timestamps, scores}, nil
+}
+
+// saliencyMap returns the magnitude of the inverse transform of the spectral residual.
+func (sr *SpectralResidual) saliencyMap(values []float64) []float64 {
+	spectrum := realFFT(values)
+	amplitudes := make([]float64, len(spectrum))
+	logAmplitudes := make([]float64, len(spectrum))
+	for i, c := range spectrum {
+		amplitudes[i] = cmplx.Abs(c)
+		logAmplitudes[i] = math.Log(math.Max(amplitudes[i], 1e-8))
+	}
+
+	averaged := movingAverage(logAmplitudes, sr.amplitudeWindowSize)
+	residual := make([]complex128, len(spectrum))
+	for i, c := range spectrum {
+		if amplitudes[i] < 1e-8 {
+			continue
+		}
+		// Keep the phase and replace the amplitude by exp(spectral residual)
+		residual[i] = c / complex(amplitudes[i], 0) * complex(math.Exp(logAmplitudes[i]-averaged[i]), 0)
+	}
+
+	transformed := ifft(residual)
+	saliency := make([]float64, len(transformed))
+	for i, c := range transformed {
+		saliency[i] = cmplx.Abs(c)
+	}
+	return saliency
+}
+
+// extrapolate appends estimated points to the values. The estimation uses the average gradient
+// of the look back points preceding the last value, so that an anomaly on the last value
+// does not leak into the estimated points.
+func (sr *SpectralResidual) extrapolate(values []float64) []float64 {
+	n := len(values)
+	window := values[maxInt(n-sr.lookBackPoints-2, 0) : n-1]
+
+	estimated := values[n-1]
+	if len(window) > 1 {
+		last := window[len(window)-1]
+		gradients := 0.0
+		for i, value := range window[:len(window)-1] {
+			gradients += (last - value) / float64(len(window)-1-i)
+		}
+		estimated = window[1] + gradients
+	}
+
+	extended := make([]float64, n, n+sr.extrapolatedPoints)
+	copy(extended, values)
+	for i := 0; i < sr.extrapolatedPoints; i++ {
+		extended = append(extended, estimated)
+	}
+	return extended
+}
+
+func (sr *SpectralResidual) sanityCheck(timeSeries *TimeSeries) error {
+	if timeSeries.Size() < 2 {
+		return errors.New("not enough data points")
+	}
+	if sr.amplitudeWindowSize < 1 || sr.scoreWindowSize < 1 || sr.extrapolatedPoints < 0 || sr.lookBackPoints < 1 {
+		return errors.New("invalid window sizes")
+	}
+	return nil
+}
+
+// movingAverage returns the centered moving average of the values, shrinking the window at the boundaries.
+func movingAverage(values []float64, windowSize int) []float64 {
+	half := windowSize / 2
+	return mapSliceWithIndex(values, func(idx int, _ float64) float64 {
+		return Average(values[maxInt(idx-half, 0):minInt(idx+windowSize-half, len(values))])
+	})
+}
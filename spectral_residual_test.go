@@ -0,0 +1,31 @@
+package anomalia
+
+import "testing"
+
+func TestRunWithSpectralResidual(t *testing.T) {
+	ts := generatePeriodicTimeSeries(300, 24)
+	ts.Values[120] += 5
+
+	scoreList := NewSpectralResidual().Run(ts)
+	if scoreList == nil {
+		t.Fatalf("score list cannot be nil")
+	}
+
+	if len(scoreList.Scores) != ts.Size() {
+		t.Fatalf("score list must have the same dimension as original time series")
+	}
+
+	if scoreList.Scores[120] != scoreList.Max() {
+		t.Fatalf("the spike must have the highest score")
+	}
+}
+
+func TestSpectralResidualScoresNewestPoints(t *testing.T) {
+	ts := generatePeriodicTimeSeries(300, 24)
+	ts.Values[299] += 5
+
+	scoreList := NewSpectralResidual().Run(ts)
+	if scoreList.Scores[299] != scoreList.Max() {
+		t.Fatalf("the spike on the newest point must have the highest score")
+	}
+}
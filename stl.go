@@ -1,6 +1,13 @@
 package anomalia
 
-import "github.com/project-anomalia/stl"
+import (
+	"errors"
+	"math"
+
+	"github.com/project-anomalia/stl"
+)
+
+var errNoSeasonality = errors.New("no seasonality found")
 
 type STLMethod int32
 
@@ -12,6 +19,26 @@ const (
 	Multiplicative
 )
 
+// STLScoring type checker for the way residuals are turned into scores
+type STLScoring int32
+
+const (
+	// RawResidual uses the signed residuals as scores.
+	RawResidual STLScoring = iota
+
+	// AbsoluteResidual uses the absolute residuals as scores.
+	AbsoluteResidual
+
+	// MADResidual divides the absolute deviation of residuals from their median by the (scaled) median absolute deviation.
+	MADResidual
+
+	// IQRResidual divides the absolute deviation of residuals from their median by the (scaled) interquartile range.
+	IQRResidual
+
+	// RollingZScore uses the absolute z-score of residuals over a rolling window.
+	RollingZScore
+)
+
 // STL holds Seasonal-Trend With Loess algorithm configuration.
 //
 // The STL algorithm decomposes a time series into seasonal, trend and remainder components.
@@ -25,6 +52,11 @@ type STL struct {
 	trendConfig         *stl.Config
 	lowPassFilterConfig *stl.Config
 	method              stl.ModelType
+	methodType          STLMethod
+	autoPeriod          bool
+	fallback            Algorithm
+	scoring             STLScoring
+	scoringWindowSize   int
 }
 
 // NewSTL returns an instance of the STL struct.
@@ -33,9 +65,18 @@ func NewSTL() *STL {
 		robustIterations: stl.WithRobustIter(0),
 		iterations:       stl.WithIter(2),
 		method:           stl.Additive(),
+		fallback:         NewWeightedSum(),
+		scoring:          RawResidual,
 	}
 }
 
+// NewRobustSTL returns an instance of the STL struct suited for anomaly detection.
+// It uses robust iterations so that outliers do not leak into the seasonal and trend components,
+// and scores residuals by their deviation from the median relative to the median absolute deviation.
+func NewRobustSTL() *STL {
+	return NewSTL().RobustIterations(15).Scoring(MADResidual)
+}
+
 func (s *STL) Periodicity(p int) *STL {
 	s.periodicity = p
 	return s
@@ -71,6 +112,33 @@ func (s *STL) LowPassFilterConfig(config *stl.Config) *STL {
 	return s
 }
 
+// AutoPeriod tells the algorithm to detect the periodicity of the time series using DetectPeriod.
+// When no seasonality is found, the fallback algorithm is used instead.
+// If the width is not set, it defaults to twice the detected periodicity plus one.
+func (s *STL) AutoPeriod(auto bool) *STL {
+	s.autoPeriod = auto
+	return s
+}
+
+// Fallback sets the non-seasonal algorithm used when AutoPeriod finds no seasonality (defaults to WeightedSum).
+func (s *STL) Fallback(algorithm Algorithm) *STL {
+	s.fallback = algorithm
+	return s
+}
+
+// Scoring sets how residuals are turned into scores (defaults to RawResidual).
+func (s *STL) Scoring(scoring STLScoring) *STL {
+	s.scoring = scoring
+	return s
+}
+
+// ScoringWindowSize sets the rolling window size used by the RollingZScore scoring.
+// When 0 (default), the periodicity is used.
+func (s *STL) ScoringWindowSize(size int) *STL {
+	s.scoringWindowSize = size
+	return s
+}
+
 func (s *STL) MethodType(method STLMethod) *STL {
 	switch method {
 	case Additive:
@@ -80,6 +148,7 @@ func (s *STL) MethodType(method STLMethod) *STL {
 	default:
 		panic("invalid STL method type")
 	}
+	s.methodType = method
 	return s
 }
 
@@ -89,7 +158,22 @@ func (s *STL) Run(timeSeries *TimeSeries) *ScoreList {
 	return scoreList
 }
 
-func (s *STL) computeScores(timeSeries *TimeSeries) (*ScoreList, error) {
+// Decompose decomposes the time series into its trend, seasonal and residual components.
+// It returns an error when AutoPeriod is enabled and no seasonality is found.
+func (s *STL) Decompose(timeSeries *TimeSeries) (*Decomposition, error) {
+	periodicity, width := s.periodicity, s.width
+	if s.autoPeriod {
+		candidates, err := DetectPeriod(timeSeries)
+		if err != nil || len(candidates) == 0 || candidates[0].Confidence < minSeasonalityConfidence {
+			return nil, errNoSeasonality
+		}
+
+		periodicity = candidates[0].Period
+		if width <= 0 {
+			width = 2*periodicity + 1
+		}
+	}
+
 	options := []stl.Opt{s.iterations, s.robustIterations}
 
 	if s.seasonalConfig != nil {
@@ -104,9 +188,85 @@ func (s *STL) computeScores(timeSeries *TimeSeries) (*ScoreList, error) {
 		options = append(options, stl.WithLowpassConfig(*s.lowPassFilterConfig))
 	}
 
-	result := stl.Decompose(timeSeries.Values, s.periodicity, s.width, s.method, options...)
+	// The model transformation happens in place so the time series values are copied
+	result := stl.Decompose(copySlice(timeSeries.Values), periodicity, width, s.method, options...)
 	if result.Err != nil {
 		return nil, result.Err
 	}
-	return &ScoreList{timeSeries.Timestamps, result.Resid}, nil
+
+	decomposition := &Decomposition{
+		Periodicity: periodicity,
+		Trend:       NewTimeSeries(timeSeries.Timestamps, result.Trend),
+		Seasonal:    NewTimeSeries(timeSeries.Timestamps, result.Seasonal),
+		Residual:    NewTimeSeries(timeSeries.Timestamps, result.Resid),
+	}
+	decomposition.computeStrengths(s.methodType == Multiplicative)
+	return decomposition, nil
+}
+
+func (s *STL) computeScores(timeSeries *TimeSeries) (*ScoreList, error) {
+	decomposition, err := s.Decompose(timeSeries)
+	if err == errNoSeasonality {
+		return s.fallback.computeScores(timeSeries)
+	} else if err != nil {
+		return nil, err
+	}
+	return &ScoreList{timeSeries.Timestamps, s.scoreResiduals(decomposition)}, nil
+}
+
+func (s *STL) scoreResiduals(decomposition *Decomposition) []float64 {
+	// Multiplicative residuals are centered around 1
+	baseline := 0.0
+	if s.methodType == Multiplicative {
+		baseline = 1.0
+	}
+
+	windowSize := s.scoringWindowSize
+	if windowSize <= 0 {
+		windowSize = decomposition.Periodicity
+	}
+	return scoreResiduals(decomposition.Residual.Values, s.scoring, baseline, windowSize)
+}
+
+// scoreResiduals turns the residuals into scores using the scoring mode.
+func scoreResiduals(residuals []float64, scoring STLScoring, baseline float64, windowSize int) []float64 {
+	switch scoring {
+	case AbsoluteResidual:
+		return mapSlice(residuals, func(residual float64) float64 { return math.Abs(residual - baseline) })
+	case MADResidual:
+		// Scaled so that the spread is consistent with the standard deviation of normally distributed residuals
+		return scaleDeviations(residuals, 1.4826*MedianAbsoluteDeviation(residuals))
+	case IQRResidual:
+		return scaleDeviations(residuals, (Percentile(residuals, 75)-Percentile(residuals, 25))/1.349)
+	case RollingZScore:
+		return rollingZScores(residuals, windowSize)
+	default:
+		return residuals
+	}
+}
+
+// scaleDeviations returns the absolute deviations from the median divided by the spread.
+func scaleDeviations(residuals []float64, spread float64) []float64 {
+	median := Median(residuals)
+	return mapSlice(residuals, func(residual float64) float64 {
+		if spread == 0 {
+			return math.Abs(residual - median)
+		}
+		return math.Abs(residual-median) / spread
+	})
+}
+
+// rollingZScores returns the absolute z-score of each value relative to the lagging window preceding it.
+func rollingZScores(values []float64, windowSize int) []float64 {
+	return mapSliceWithIndex(values, func(idx int, value float64) float64 {
+		window := values[maxInt(idx-windowSize, 0):idx]
+		if len(window) < 2 {
+			return 0.0
+		}
+		stdev := Stdev(window)
+		if stdev == 0 {
+			return 0.0
+		}
+		return math.Abs(value-Average(window)) / stdev
+	})
 }
@@ -1,6 +1,9 @@
 package anomalia
 
-import "testing"
+import (
+	"math"
+	"testing"
+)
 
 func TestRunWithSTL(t *testing.T) {
 	ts := NewTimeSeriesFromCSV("testdata/co2.csv")
@@ -14,3 +17,78 @@ func TestRunWithSTL(t *testing.T) {
 		t.Fatalf("score list must have the same dimension as original time series")
 	}
 }
+
+func TestRunWithAutoPeriodSTL(t *testing.T) {
+	ts := NewTimeSeriesFromCSV("testdata/co2.csv")
+	scoreList := NewSTL().AutoPeriod(true).Run(ts)
+	expected := NewSTL().Width(25).Periodicity(12).Run(ts)
+
+	for i := range expected.Scores {
+		if scoreList.Scores[i] != expected.Scores[i] {
+			t.Fatalf("detected periodicity must be used to decompose the time series")
+		}
+	}
+
+	// No seasonality in random noise, so the fallback algorithm is used
+	noise := generateFakeTimeSeries(300)
+	if scoreList := NewSTL().AutoPeriod(true).Fallback(NewEma()).Run(noise); scoreList == nil {
+		t.Fatalf("score list cannot be nil")
+	}
+}
+
+func TestDecomposeWithSTL(t *testing.T) {
+	ts := NewTimeSeriesFromCSV("testdata/co2.csv")
+	decomposition, err := NewSTL().Width(35).Periodicity(12).Decompose(ts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i := range ts.Values {
+		sum := decomposition.Trend.Values[i] + decomposition.Seasonal.Values[i] + decomposition.Residual.Values[i]
+		if math.Abs(sum-ts.Values[i]) > 1e-6 {
+			t.Fatalf("components must add up to the original time series")
+		}
+	}
+
+	if decomposition.TrendStrength < 0.9 {
+		t.Fatalf("co2 levels have a strong trend, got %v", decomposition.TrendStrength)
+	}
+
+	if decomposition.SeasonalStrength <= 0 || decomposition.SeasonalStrength > 1 {
+		t.Fatalf("seasonal strength must be within (0, 1], got %v", decomposition.SeasonalStrength)
+	}
+
+	if _, err := NewSTL().AutoPeriod(true).Decompose(generateFakeTimeSeries(300)); err == nil {
+		t.Fatalf("must fail when no seasonality is found")
+	}
+}
+
+func TestScoringModesWithSTL(t *testing.T) {
+	ts := NewTimeSeriesFromCSV("testdata/airline-passengers.csv")
+	for _, scoring := range []STLScoring{AbsoluteResidual, MADResidual, IQRResidual, RollingZScore} {
+		scoreList := NewSTL().Width(15).Periodicity(12).MethodType(Multiplicative).Scoring(scoring).Run(ts)
+		if scoreList == nil {
+			t.Fatalf("score list cannot be nil")
+		}
+
+		if scoreList.Min() < 0 {
+			t.Fatalf("scores must not be negative (scoring mode %d)", scoring)
+		}
+	}
+}
+
+func TestRunWithRobustSTL(t *testing.T) {
+	ts := NewTimeSeriesFromCSV("testdata/co2.csv")
+	values := copySlice(ts.Values)
+	values[300] -= 20
+	dip := NewTimeSeries(ts.Timestamps, values)
+
+	scoreList := NewRobustSTL().Width(35).Periodicity(12).Run(dip)
+	if scoreList.Scores[300] != scoreList.Max() {
+		t.Fatalf("negative dip must have the highest score")
+	}
+
+	if scoreList.Scores[300] < 2.0 {
+		t.Fatalf("negative dip must cross the default detector threshold, got %v", scoreList.Scores[300])
+	}
+}
@@ -3,6 +3,8 @@ package anomalia
 import (
 	"encoding/csv"
 	"encoding/json"
+	"fmt"
+	"io"
 	"os"
 	"strconv"
 )
@@ -53,6 +55,47 @@ func NewTimeSeriesFromCSV(path string) *TimeSeries {
 	return NewTimeSeries(timestamps, data)
 }
 
+// LoadTimeSeriesFromCSV loads a time series from a CSV file with a header, a timestamp and a value per row.
+// Unlike NewTimeSeriesFromCSV, it returns an error reporting the failing line instead of skipping invalid rows.
+func LoadTimeSeriesFromCSV(path string) (*TimeSeries, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	if _, err := r.Read(); err != nil {
+		return nil, fmt.Errorf("%s: missing header: %v", path, err)
+	}
+
+	var timestamps, data []float64
+	for line := 2; ; line++ {
+		rec, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		if len(rec) < 2 {
+			return nil, fmt.Errorf("%s:%d: expected a timestamp and a value", path, line)
+		}
+
+		timestamp, err := strconv.ParseFloat(rec[0], 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid timestamp %q", path, line, rec[0])
+		}
+		value, err := strconv.ParseFloat(rec[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid value %q", path, line, rec[1])
+		}
+		timestamps = append(timestamps, timestamp)
+		data = append(data, value)
+	}
+	return NewTimeSeries(timestamps, data), nil
+}
+
 // EarliestTimestamp returns the earliest timestamp in the time series
 func (ts *TimeSeries) EarliestTimestamp() float64 {
 	min, _ := minMax(ts.Timestamps)
@@ -126,14 +169,7 @@ func (ts *TimeSeries) Stdev() float64 {
 
 // Median calculates median value over the time series.
 func (ts *TimeSeries) Median() float64 {
-	sorted := sortedCopy(ts.Values)
-	length := len(sorted)
-	mid := length / 2
-
-	if length%2 == 0 {
-		return (sorted[mid-1] + sorted[mid]) / 2
-	}
-	return sorted[mid]
+	return Median(ts.Values)
 }
 
 // Align aligns two time series so that they have the same dimension and same timestamps
@@ -199,6 +235,19 @@ func (ts *TimeSeries) Size() int {
 	return len(ts.Timestamps)
 }
 
+// samplingInterval returns the median interval between consecutive timestamps.
+func (ts *TimeSeries) samplingInterval() float64 {
+	if ts.Size() < 2 {
+		return 0.0
+	}
+	sorted := sortedCopy(ts.Timestamps)
+	intervals := make([]float64, len(sorted)-1)
+	for i := 1; i < len(sorted); i++ {
+		intervals[i-1] = sorted[i] - sorted[i-1]
+	}
+	return Median(intervals)
+}
+
 // String returns JSON representation of the time series
 func (ts *TimeSeries) String() string {
 	out, err := json.Marshal(ts)
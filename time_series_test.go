@@ -1,8 +1,12 @@
 package anomalia
 
 import (
+	"io/ioutil"
 	"math/big"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -28,6 +32,33 @@ func TestNewTimeSeries(t *testing.T) {
 	NewTimeSeries([]float64{1, 2}, []float64{1})
 }
 
+func TestLoadTimeSeriesFromCSV(t *testing.T) {
+	ts, err := LoadTimeSeriesFromCSV("testdata/airline-passengers.csv")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(ts, NewTimeSeriesFromCSV("testdata/airline-passengers.csv")) {
+		t.Fatalf("valid files must load the same time series")
+	}
+
+	dir, err := ioutil.TempDir("", "anomalia")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "malformed.csv")
+	if err := ioutil.WriteFile(path, []byte("timestamp,value\n1,2\noops,oops\n"), 0644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := LoadTimeSeriesFromCSV(path); err == nil || !strings.Contains(err.Error(), ":3:") {
+		t.Fatalf("must report the failing line, got %v", err)
+	}
+	if _, err := LoadTimeSeriesFromCSV(filepath.Join(dir, "missing.csv")); err == nil {
+		t.Fatalf("must fail when the file does not exist")
+	}
+}
+
 func TestEarliestTimestamp(t *testing.T) {
 	timestamp := NewTimeSeries(timestamps, values).EarliestTimestamp()
 	actual := big.NewFloat(timestamp)
@@ -14,3 +14,16 @@ func TestRunWithWeightedSum(t *testing.T) {
 		t.Fatalf("score list and time series dimensions do not match")
 	}
 }
+
+func TestWeightedSumIsNotForecaster(t *testing.T) {
+	ws := NewWeightedSum()
+	ws.LagWindowSize(3)
+	if ws.ExponentialMovingAverage.lagWindowSize != 3 {
+		t.Fatalf("lag window size must be set on the embedded moving average")
+	}
+
+	var scorer interface{} = ws
+	if _, ok := scorer.(Forecaster); ok {
+		t.Fatalf("weighted sum must not expose the forecasting API")
+	}
+}